@@ -29,25 +29,43 @@ type App struct {
 	cancel   func()
 	lk       sync.Mutex
 	instance *registry.ServiceInstance
+	stopOnce sync.Once
+	stopErr  error
 }
 
 type appKey struct{}
 
+// Stop gracefully stops the application, only the first call runs the BeforeStop
+// hooks and deregisters the instance, the later ones return its error.
 func (a *App) Stop() error {
+	a.stopOnce.Do(func() {
+		a.stopErr = a.stop()
+	})
+	return a.stopErr
+}
+
+func (a *App) stop() error {
+	var err error
+	sctx := NewContext(a.opts.ctx, a)
+	for _, fn := range a.opts.beforeStop {
+		if hErr := fn(sctx); hErr != nil && err == nil {
+			err = hErr
+		}
+	}
 	a.lk.Lock()
 	instance := a.instance
 	a.lk.Unlock()
 	if a.opts.registrar != nil && instance != nil {
 		ctx, cancel := context.WithTimeout(a.opts.ctx, a.opts.registrarTimeout)
 		defer cancel()
-		if err := a.opts.registrar.Deregister(ctx, instance); err != nil {
-			return err
+		if dErr := a.opts.registrar.Deregister(ctx, instance); dErr != nil && err == nil {
+			err = dErr
 		}
 	}
 	if a.cancel != nil {
 		a.cancel()
 	}
-	return nil
+	return err
 }
 
 // Run executes all OnStart hooks registered with the application's Lifecycle.
@@ -57,9 +75,14 @@ func (a *App) Run() error {
 	if err != nil {
 		return err
 	}
-	ctx := NewContext(a.ctx, a)
+	sctx := NewContext(a.ctx, a)
+	for _, fn := range a.opts.beforeStart {
+		if err := fn(sctx); err != nil {
+			return err
+		}
+	}
 	// https://www.fullstory.com/blog/why-errgroup-withcontext-in-golang-server-handlers/
-	eg, ctx := errgroup.WithContext(ctx)
+	eg, ctx := errgroup.WithContext(sctx)
 	wg := sync.WaitGroup{}
 	for _, srv := range a.opts.servers {
 		server := srv
@@ -76,15 +99,26 @@ func (a *App) Run() error {
 		})
 	}
 	wg.Wait()
+	var startErr error
 	if a.opts.registrar != nil {
 		rCtx, rCancel := context.WithTimeout(a.opts.ctx, a.opts.registrarTimeout)
 		defer rCancel()
-		if err := a.opts.registrar.Register(rCtx, instance); err != nil {
-			return err
+		if startErr = a.opts.registrar.Register(rCtx, instance); startErr == nil {
+			a.lk.Lock()
+			a.instance = instance
+			a.lk.Unlock()
 		}
-		a.lk.Lock()
-		a.instance = instance
-		a.lk.Unlock()
+	}
+	if startErr == nil {
+		for _, fn := range a.opts.afterStart {
+			if startErr = fn(sctx); startErr != nil {
+				break
+			}
+		}
+	}
+	if startErr != nil {
+		// stop the started servers and wait for them below
+		_ = a.Stop()
 	}
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, a.opts.sigs...)
@@ -102,16 +136,37 @@ func (a *App) Run() error {
 
 		}
 	})
-	if err := eg.Wait(); err != nil && !errors.Is(err, context.Canceled) {
-		return err
+	err = eg.Wait()
+	if errors.Is(err, context.Canceled) {
+		err = nil
 	}
-	return nil
+	// the servers may exit without Stop, e.g. when one of them fails to start
+	if sErr := a.Stop(); sErr != nil && err == nil {
+		err = sErr
+	}
+	if startErr != nil {
+		err = startErr
+	}
+	stopCtx := NewContext(a.opts.ctx, a)
+	for _, fn := range a.opts.afterStop {
+		if hErr := fn(stopCtx); hErr != nil && err == nil {
+			err = hErr
+		}
+	}
+	return err
 }
 
+// NewContext returns a new Context that carries value.
 func NewContext(ctx context.Context, a AppInfo) context.Context {
 	return context.WithValue(ctx, appKey{}, a)
 }
 
+// FromContext returns the AppInfo value stored in ctx, if any.
+func FromContext(ctx context.Context) (a AppInfo, ok bool) {
+	a, ok = ctx.Value(appKey{}).(AppInfo)
+	return
+}
+
 func New(opts ...Option) *App {
 	o := options{
 		ctx:              context.Background(),
@@ -152,6 +207,8 @@ func (a *App) Metadata() map[string]string {
 }
 
 func (a *App) Endpoint() []string {
+	a.lk.Lock()
+	defer a.lk.Unlock()
 	if a.instance == nil {
 		return []string{}
 	}
@@ -170,7 +227,9 @@ func (a *App) buildInstance() (*registry.ServiceInstance, error) {
 				if err != nil {
 					return nil, err
 				}
-				endpoints = append(endpoints, e.String())
+				if e != nil {
+					endpoints = append(endpoints, e.String())
+				}
 			}
 		}
 	}
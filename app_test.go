@@ -2,16 +2,21 @@ package kratos_cloned
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/tiennampham23/kratos-cloned/registry"
 	"github.com/tiennampham23/kratos-cloned/transport/http"
+	"io/ioutil"
+	stdhttp "net/http"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 )
 
 type mockRegistry struct {
-	lk sync.Mutex
+	lk      sync.Mutex
 	service map[string]*registry.ServiceInstance
 }
 
@@ -35,7 +40,6 @@ func (r *mockRegistry) Deregister(ctx context.Context, service *registry.Service
 	return nil
 }
 
-
 func TestApp(t *testing.T) {
 	hs := http.NewServer()
 	app := New(
@@ -47,9 +51,254 @@ func TestApp(t *testing.T) {
 		}),
 	)
 	time.AfterFunc(time.Second, func() {
+		if len(app.Endpoint()) != 1 || !strings.HasPrefix(app.Endpoint()[0], "http://") {
+			t.Errorf("expected registered http endpoint, got %v", app.Endpoint())
+		}
+		_ = app.Stop()
+	})
+	if err := app.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApp_StopInFlight(t *testing.T) {
+	started := make(chan struct{})
+	hs := http.NewServer(http.Address("127.0.0.1:0"))
+	hs.HandleFunc("/slow", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+			stdhttp.Error(w, r.Context().Err().Error(), stdhttp.StatusServiceUnavailable)
+		case <-time.After(200 * time.Millisecond):
+			_, _ = w.Write([]byte("done"))
+		}
+	})
+	u, err := hs.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := New(Name("kratos"), Server(hs))
+
+	result := make(chan string, 1)
+	go func() {
+		res, err := stdhttp.Get(fmt.Sprintf("http://%s/slow", u.Host))
+		if err != nil {
+			result <- err.Error()
+			return
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		result <- string(body)
+	}()
+	go func() {
+		<-started
+		// the request in flight completes during the graceful stop
+		_ = app.Stop()
+	}()
+	if err := app.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-result; got != "done" {
+		t.Errorf("expected the in-flight request to complete, got %q", got)
+	}
+}
+
+func TestApp_UnixServer(t *testing.T) {
+	hs := http.NewServer(http.Network("unix"), http.Address(filepath.Join(t.TempDir(), "kratos.sock")))
+	app := New(Name("kratos"), Server(hs))
+	time.AfterFunc(100*time.Millisecond, func() {
+		// the unix socket server has no registry endpoint
+		if len(app.Endpoint()) != 0 {
+			t.Errorf("expected no endpoint, got %v", app.Endpoint())
+		}
+		_ = app.Stop()
+	})
+	if err := app.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApp_Hooks(t *testing.T) {
+	var (
+		lk    sync.Mutex
+		calls []string
+	)
+	hook := func(name string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if _, ok := ctx.Value(appKey{}).(AppInfo); !ok {
+				t.Errorf("%s: app info not found in context", name)
+			}
+			lk.Lock()
+			calls = append(calls, name)
+			lk.Unlock()
+			return nil
+		}
+	}
+	app := New(
+		Name("kratos"),
+		BeforeStart(hook("beforeStart")),
+		AfterStart(hook("afterStart")),
+		BeforeStop(hook("beforeStop")),
+		AfterStop(hook("afterStop")),
+	)
+	time.AfterFunc(100*time.Millisecond, func() {
 		_ = app.Stop()
 	})
 	if err := app.Run(); err != nil {
 		t.Fatal(err)
 	}
-}
\ No newline at end of file
+	expected := "beforeStart,afterStart,beforeStop,afterStop"
+	lk.Lock()
+	defer lk.Unlock()
+	if got := strings.Join(calls, ","); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestApp_BeforeStartError(t *testing.T) {
+	started := false
+	app := New(
+		BeforeStart(func(context.Context) error {
+			return errors.New("migration failed")
+		}),
+		AfterStart(func(context.Context) error {
+			started = true
+			return nil
+		}),
+	)
+	if err := app.Run(); err == nil || err.Error() != "migration failed" {
+		t.Fatalf("expected migration failed error, got %v", err)
+	}
+	if started {
+		t.Error("after start hook should not run when before start fails")
+	}
+}
+
+func TestApp_AfterStopOnStartError(t *testing.T) {
+	stopped := false
+	app := New(
+		AfterStart(func(context.Context) error {
+			return errors.New("warmup failed")
+		}),
+		AfterStop(func(context.Context) error {
+			stopped = true
+			return nil
+		}),
+	)
+	if err := app.Run(); err == nil || err.Error() != "warmup failed" {
+		t.Fatalf("expected warmup failed error, got %v", err)
+	}
+	if !stopped {
+		t.Error("after stop hook should run when after start fails")
+	}
+}
+
+func TestApp_FromContext(t *testing.T) {
+	app := New(ID("1"), Name("kratos"), Version("v1.0.0"))
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatal("expected no app info in empty context")
+	}
+	info, ok := FromContext(NewContext(context.Background(), app))
+	if !ok {
+		t.Fatal("expected app info in context")
+	}
+	if info.ID() != "1" || info.Name() != "kratos" || info.Version() != "v1.0.0" {
+		t.Errorf("unexpected app info: %s %s %s", info.ID(), info.Name(), info.Version())
+	}
+}
+
+type failedRegistry struct {
+	mockRegistry
+}
+
+func (r *failedRegistry) Register(context.Context, *registry.ServiceInstance) error {
+	return errors.New("register failed")
+}
+
+type failedServer struct{}
+
+func (failedServer) Start(context.Context) error { return errors.New("listen failed") }
+func (failedServer) Stop(context.Context) error  { return nil }
+
+func TestApp_RegisterError(t *testing.T) {
+	var (
+		lk    sync.Mutex
+		calls []string
+	)
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			lk.Lock()
+			defer lk.Unlock()
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	app := New(
+		Server(http.NewServer()),
+		Registrar(&failedRegistry{}),
+		AfterStart(record("afterStart")),
+		BeforeStop(record("beforeStop")),
+		AfterStop(record("afterStop")),
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Run()
+	}()
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "register failed" {
+			t.Fatalf("expected register failed error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run does not return after the register error")
+	}
+	if got := strings.Join(calls, ","); got != "beforeStop,afterStop" {
+		t.Errorf("unexpected hook calls: %s", got)
+	}
+}
+
+func TestApp_StopOnce(t *testing.T) {
+	var (
+		lk    sync.Mutex
+		stops int
+	)
+	app := New(
+		Server(http.NewServer()),
+		BeforeStop(func(context.Context) error {
+			lk.Lock()
+			defer lk.Unlock()
+			stops++
+			return errors.New("flush failed")
+		}),
+	)
+	time.AfterFunc(500*time.Millisecond, func() {
+		_ = app.Stop()
+		_ = app.Stop()
+	})
+	if err := app.Run(); err == nil || err.Error() != "flush failed" {
+		t.Fatalf("expected flush failed error, got %v", err)
+	}
+	if err := app.Stop(); err == nil || err.Error() != "flush failed" {
+		t.Errorf("expected the error of the first stop, got %v", err)
+	}
+	if stops != 1 {
+		t.Errorf("expected before stop hooks to run once, ran %d times", stops)
+	}
+}
+
+func TestApp_ServerStartError(t *testing.T) {
+	stopped := false
+	app := New(
+		Server(failedServer{}),
+		BeforeStop(func(context.Context) error {
+			stopped = true
+			return nil
+		}),
+	)
+	if err := app.Run(); err == nil || err.Error() != "listen failed" {
+		t.Fatalf("expected listen failed error, got %v", err)
+	}
+	if !stopped {
+		t.Error("before stop hook should run when a server fails to start")
+	}
+}
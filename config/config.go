@@ -0,0 +1,254 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrNotFound is key not found.
+	ErrNotFound = errors.New("key not found")
+	// ErrTypeAssert is type assert error.
+	ErrTypeAssert = errors.New("type assert error")
+
+	_ Config = (*config)(nil)
+)
+
+// Observer is config observer, it is called with the key and its new value.
+type Observer func(string, Value)
+
+// Config is a config interface.
+type Config interface {
+	Load() error
+	Scan(v interface{}) error
+	Value(key string) Value
+	Watch(key string, o Observer) error
+	Close() error
+}
+
+type config struct {
+	opts   options
+	reader Reader
+	// cached holds a *sync.Map of the values read since the last update,
+	// it is swapped as a whole when the sources change.
+	cached atomic.Value
+
+	// lock serializes the updates of the sources.
+	lock     sync.Mutex
+	kvs      [][]*KeyValue
+	watchers []Watcher
+
+	olock     sync.RWMutex
+	observers map[string][]Observer
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New new a config with options.
+func New(opts ...Option) Config {
+	o := options{
+		decoder:  defaultDecoder,
+		resolver: defaultResolver,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := &config{
+		opts:      o,
+		reader:    newReader(o),
+		observers: make(map[string][]Observer),
+		done:      make(chan struct{}),
+	}
+	c.cached.Store(new(sync.Map))
+	return c
+}
+
+// Load loads all the sources in order and merges them, the later sources override the earlier ones.
+// The first Load then watches every source, the later ones only reload the sources.
+func (c *config) Load() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	kvs := make([][]*KeyValue, len(c.opts.sources))
+	all := make([]*KeyValue, 0)
+	for i, src := range c.opts.sources {
+		skvs, err := src.Load()
+		if err != nil {
+			return err
+		}
+		kvs[i] = skvs
+		all = append(all, skvs...)
+	}
+	// rebuild from all the sources at once, so the placeholders can refer to any of them
+	// and the keys removed since the last Load are dropped
+	if err := c.reader.Reset(all...); err != nil {
+		return err
+	}
+	c.kvs = kvs
+	c.cached.Store(new(sync.Map))
+	if c.watchers != nil {
+		return nil
+	}
+	watchers := make([]Watcher, 0, len(c.opts.sources))
+	for _, src := range c.opts.sources {
+		w, err := src.Watch()
+		if err != nil {
+			for _, w := range watchers {
+				_ = w.Stop()
+			}
+			return err
+		}
+		watchers = append(watchers, w)
+	}
+	// the watchers start only once all of them are created
+	c.watchers = watchers
+	for i, w := range watchers {
+		go c.watch(i, w)
+	}
+	return nil
+}
+
+func (c *config) watch(i int, w Watcher) {
+	for {
+		kvs, err := w.Next()
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			log.Errorf("[config] failed to watch next config: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		if err = c.update(i, kvs); err != nil {
+			log.Errorf("[config] failed to update config: %v", err)
+		}
+	}
+}
+
+// update replaces the key values of the i-th source, re-merges all the sources
+// and notifies the observers of the keys whose values changed.
+func (c *config) update(i int, kvs []*KeyValue) error {
+	notify, err := c.reset(i, kvs)
+	if err != nil {
+		return err
+	}
+	// the observers are called without the lock, so they can read and reload the config
+	for _, n := range notify {
+		n()
+	}
+	return nil
+}
+
+// reset re-merges the sources with the key values of the i-th source replaced,
+// it returns the notifications of the observers of the changed keys.
+func (c *config) reset(i int, kvs []*KeyValue) ([]func(), error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	type value struct {
+		v  interface{}
+		ok bool
+	}
+	c.olock.RLock()
+	olds := make(map[string]value, len(c.observers))
+	for key := range c.observers {
+		var old value
+		if v, ok := c.reader.Value(key); ok {
+			old = value{v: v.Load(), ok: true}
+		}
+		olds[key] = old
+	}
+	c.olock.RUnlock()
+
+	all := make([]*KeyValue, 0)
+	for j, skvs := range c.kvs {
+		if j == i {
+			skvs = kvs
+		}
+		all = append(all, skvs...)
+	}
+	if err := c.reader.Reset(all...); err != nil {
+		return nil, err
+	}
+	c.kvs[i] = kvs
+	c.cached.Store(new(sync.Map))
+
+	notify := make([]func(), 0)
+	for key, old := range olds {
+		_, ok := c.reader.Value(key)
+		v := c.Value(key)
+		if ok == old.ok && reflect.DeepEqual(old.v, v.Load()) {
+			continue
+		}
+		c.olock.RLock()
+		obs := c.observers[key]
+		c.olock.RUnlock()
+		for _, o := range obs {
+			key, o := key, o
+			notify = append(notify, func() { o(key, v) })
+		}
+	}
+	return notify, nil
+}
+
+// Value returns the value of the dotted key path, the returned value reports ErrNotFound if the key does not exist.
+func (c *config) Value(key string) Value {
+	cached := c.cached.Load().(*sync.Map)
+	if v, ok := cached.Load(key); ok {
+		return v.(Value)
+	}
+	if v, ok := c.reader.Value(key); ok {
+		cached.Store(key, v)
+		return v
+	}
+	return &errValue{err: ErrNotFound}
+}
+
+// Scan decodes the whole merged config into v.
+func (c *config) Scan(v interface{}) error {
+	data, err := c.reader.Source()
+	if err != nil {
+		return err
+	}
+	return unmarshalJSON(data, v)
+}
+
+// Watch registers the observer of the key, it is called whenever the value of the key changes,
+// with a value reporting ErrNotFound once the key is removed.
+func (c *config) Watch(key string, o Observer) error {
+	// a key whose value is null exists
+	if _, ok := c.reader.Value(key); !ok {
+		return ErrNotFound
+	}
+	c.olock.Lock()
+	c.observers[key] = append(c.observers[key], o)
+	c.olock.Unlock()
+	return nil
+}
+
+// Close stops all the source watchers.
+func (c *config) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.lock.Lock()
+		watchers := c.watchers
+		c.lock.Unlock()
+		for _, w := range watchers {
+			if e := w.Stop(); e != nil && err == nil {
+				err = e
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,361 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSource struct {
+	kvs      []*KeyValue
+	err      error
+	w        *testWatcher
+	watchErr error
+	watches  int
+}
+
+func (s *testSource) Load() ([]*KeyValue, error) {
+	return s.kvs, s.err
+}
+
+func (s *testSource) Watch() (Watcher, error) {
+	s.watches++
+	if s.watchErr != nil {
+		return nil, s.watchErr
+	}
+	if s.w == nil {
+		s.w = newTestWatcher()
+	}
+	return s.w, nil
+}
+
+type testWatcher struct {
+	ch   chan []*KeyValue
+	done chan struct{}
+	once sync.Once
+}
+
+func newTestWatcher() *testWatcher {
+	return &testWatcher{ch: make(chan []*KeyValue), done: make(chan struct{})}
+}
+
+func (w *testWatcher) Next() ([]*KeyValue, error) {
+	select {
+	case kvs := <-w.ch:
+		return kvs, nil
+	case <-w.done:
+		return nil, context.Canceled
+	}
+}
+
+func (w *testWatcher) Stop() error {
+	w.once.Do(func() { close(w.done) })
+	return nil
+}
+
+const (
+	testJSON = `{
+	"server": {
+		"http": {"addr": "0.0.0.0:8000", "timeout": "1s"},
+		"grpc": {"addr": "0.0.0.0:9000", "timeout": 0.5}
+	},
+	"debug": true,
+	"ratio": 0.25,
+	"endpoints": ["a", "b"]
+}`
+	testYAML = `
+server:
+  http:
+    addr: 127.0.0.1:8080
+  grpc:
+    port: 9001
+`
+)
+
+func TestConfig(t *testing.T) {
+	c := New(WithSource(
+		&testSource{kvs: []*KeyValue{{Key: "config.json", Value: []byte(testJSON), Format: "json"}}},
+		&testSource{kvs: []*KeyValue{
+			{Key: "config.yml", Value: []byte(testYAML), Format: "yml"},
+			{Key: "app.name", Value: []byte("demo")},
+		}},
+	))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// the later source overrides the earlier one
+	if addr, err := c.Value("server.http.addr").String(); err != nil || addr != "127.0.0.1:8080" {
+		t.Errorf("server.http.addr = %q, %v", addr, err)
+	}
+	// the keys missing in the later source are kept
+	if d, err := c.Value("server.http.timeout").Duration(); err != nil || d != time.Second {
+		t.Errorf("server.http.timeout = %v, %v", d, err)
+	}
+	if addr, err := c.Value("server.grpc.addr").String(); err != nil || addr != "0.0.0.0:9000" {
+		t.Errorf("server.grpc.addr = %q, %v", addr, err)
+	}
+	if port, err := c.Value("server.grpc.port").Int(); err != nil || port != 9001 {
+		t.Errorf("server.grpc.port = %d, %v", port, err)
+	}
+	if debug, err := c.Value("debug").Bool(); err != nil || !debug {
+		t.Errorf("debug = %v, %v", debug, err)
+	}
+	if ratio, err := c.Value("ratio").Float(); err != nil || ratio != 0.25 {
+		t.Errorf("ratio = %v, %v", ratio, err)
+	}
+	if name, err := c.Value("app.name").String(); err != nil || name != "demo" {
+		t.Errorf("app.name = %q, %v", name, err)
+	}
+	endpoints, err := c.Value("endpoints").Slice()
+	if err != nil || len(endpoints) != 2 {
+		t.Fatalf("endpoints = %v, %v", endpoints, err)
+	}
+	if s, _ := endpoints[1].String(); s != "b" {
+		t.Errorf("endpoints[1] = %q", s)
+	}
+	server, err := c.Value("server").Map()
+	if err != nil || len(server) != 2 {
+		t.Errorf("server = %v, %v", server, err)
+	}
+
+	if _, err := c.Value("server.none").String(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := c.Value("server.http").Int(); !errors.Is(err, ErrTypeAssert) {
+		t.Errorf("expected ErrTypeAssert, got %v", err)
+	}
+
+	var conf struct {
+		Server struct {
+			HTTP struct {
+				Addr    string `json:"addr"`
+				Timeout string `json:"timeout"`
+			} `json:"http"`
+			GRPC struct {
+				Addr string `json:"addr"`
+				Port int    `json:"port"`
+			} `json:"grpc"`
+		} `json:"server"`
+		Debug bool `json:"debug"`
+	}
+	if err := c.Scan(&conf); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Server.HTTP.Addr != "127.0.0.1:8080" || conf.Server.HTTP.Timeout != "1s" ||
+		conf.Server.GRPC.Addr != "0.0.0.0:9000" || conf.Server.GRPC.Port != 9001 || !conf.Debug {
+		t.Errorf("unexpected scan result: %+v", conf)
+	}
+
+	var grpc struct {
+		Addr    string  `json:"addr"`
+		Timeout float64 `json:"timeout"`
+	}
+	if err := c.Value("server.grpc").Scan(&grpc); err != nil {
+		t.Fatal(err)
+	}
+	if grpc.Addr != "0.0.0.0:9000" || grpc.Timeout != 0.5 {
+		t.Errorf("unexpected value scan result: %+v", grpc)
+	}
+}
+
+func TestConfig_LoadError(t *testing.T) {
+	c := New(WithSource(&testSource{kvs: []*KeyValue{{Key: "config.ini", Value: []byte("a=b"), Format: "ini"}}}))
+	if err := c.Load(); err == nil {
+		t.Error("expected error for the unsupported format")
+	}
+	sourceErr := errors.New("source error")
+	c = New(WithSource(&testSource{err: sourceErr}))
+	if err := c.Load(); !errors.Is(err, sourceErr) {
+		t.Errorf("expected %v, got %v", sourceErr, err)
+	}
+}
+
+func TestConfig_Reload(t *testing.T) {
+	src := &testSource{kvs: []*KeyValue{{Key: "config.json", Value: []byte(`{"a":"1","b":"1"}`), Format: "json"}}}
+	c := New(WithSource(src))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	src.kvs = []*KeyValue{{Key: "config.json", Value: []byte(`{"a":"2"}`), Format: "json"}}
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := c.Value("a").String(); s != "2" {
+		t.Errorf("a = %q, want 2", s)
+	}
+	if _, err := c.Value("b").String(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the removed key b to be dropped, got %v", err)
+	}
+	if src.watches != 1 {
+		t.Errorf("expected the source to be watched once, got %d", src.watches)
+	}
+}
+
+func TestConfig_WatchError(t *testing.T) {
+	w := newTestWatcher()
+	watchErr := errors.New("watch error")
+	c := New(WithSource(
+		&testSource{w: w, kvs: []*KeyValue{{Key: "a", Value: []byte("1")}}},
+		&testSource{watchErr: watchErr},
+	))
+	if err := c.Load(); !errors.Is(err, watchErr) {
+		t.Fatalf("expected %v, got %v", watchErr, err)
+	}
+	select {
+	case <-w.done:
+	default:
+		t.Error("expected the created watcher to be stopped")
+	}
+}
+
+func TestConfig_WatchObserver(t *testing.T) {
+	w := newTestWatcher()
+	src := &testSource{w: w, kvs: []*KeyValue{{Key: "config.json", Value: []byte(`{"a":null}`), Format: "json"}}}
+	c := New(WithSource(src))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	done := make(chan string, 1)
+	// the observer reads and reloads the config without a deadlock
+	err := c.Watch("a", func(key string, v Value) {
+		if err := c.Load(); err != nil {
+			t.Error(err)
+		}
+		s, _ := c.Value(key).String()
+		done <- s
+	})
+	if err != nil {
+		t.Fatalf("expected the null key to be watched, got %v", err)
+	}
+	src.kvs = []*KeyValue{{Key: "config.json", Value: []byte(`{"a":"1"}`), Format: "json"}}
+	w.ch <- src.kvs
+	select {
+	case s := <-done:
+		if s != "1" {
+			t.Errorf("a = %q, want 1", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the observer")
+	}
+}
+
+func TestConfig_Watch(t *testing.T) {
+	w1, w2 := newTestWatcher(), newTestWatcher()
+	c := New(WithSource(
+		&testSource{w: w1, kvs: []*KeyValue{{Key: "config.json", Value: []byte(`{"a":"1","b":"1","c":"1"}`), Format: "json"}}},
+		&testSource{w: w2, kvs: []*KeyValue{{Key: "d", Value: []byte("1")}}},
+	))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.Watch("none", func(string, Value) {}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	type change struct {
+		key string
+		val string
+		err error
+	}
+	changes := make(chan change, 10)
+	observer := func(key string, v Value) {
+		s, err := v.String()
+		changes <- change{key, s, err}
+	}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := c.Watch(key, observer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// b changed, c removed, a untouched
+	w1.ch <- []*KeyValue{{Key: "config.json", Value: []byte(`{"a":"1","b":"2"}`), Format: "json"}}
+	got := map[string]change{}
+	for i := 0; i < 2; i++ {
+		select {
+		case ch := <-changes:
+			got[ch.key] = ch
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the changes")
+		}
+	}
+	if ch := got["b"]; ch.err != nil || ch.val != "2" {
+		t.Errorf("unexpected change of b: %+v", ch)
+	}
+	if ch := got["c"]; !errors.Is(ch.err, ErrNotFound) {
+		t.Errorf("unexpected change of c: %+v", ch)
+	}
+	if s, _ := c.Value("b").String(); s != "2" {
+		t.Errorf("b = %q, want 2", s)
+	}
+
+	// the other source is re-merged with the latest values of the first one
+	w2.ch <- []*KeyValue{{Key: "d", Value: []byte("2")}}
+	select {
+	case ch := <-changes:
+		if ch.key != "d" || ch.val != "2" {
+			t.Errorf("unexpected change: %+v", ch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the change of d")
+	}
+	if s, _ := c.Value("b").String(); s != "2" {
+		t.Errorf("b = %q, want 2", s)
+	}
+	select {
+	case ch := <-changes:
+		t.Errorf("unexpected change: %+v", ch)
+	default:
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfig_WatchConcurrentRead(t *testing.T) {
+	w := newTestWatcher()
+	c := New(WithSource(&testSource{w: w, kvs: []*KeyValue{{Key: "a", Value: []byte("0")}}}))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, err := c.Value("a").Int(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 1; i <= 100; i++ {
+		w.ch <- []*KeyValue{{Key: "a", Value: []byte(fmt.Sprint(i))}}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,141 @@
+package env
+
+import (
+	"github.com/tiennampham23/kratos-cloned/config"
+	"os"
+	"sort"
+	"strings"
+)
+
+var _ config.Source = (*env)(nil)
+
+// Option is env source option.
+type Option func(o *options)
+
+// KeyFunc maps the variable name, without the prefix, onto the dotted config key,
+// an empty key skips the variable.
+type KeyFunc func(name string) string
+
+type options struct {
+	prefixes []string
+	keyFunc  KeyFunc
+}
+
+// WithPrefix collects the variables with one of the prefixes.
+func WithPrefix(prefixes ...string) Option {
+	return func(o *options) {
+		o.prefixes = prefixes
+	}
+}
+
+// WithKeyFunc maps the variable names by f instead of DefaultKeyFunc,
+// e.g. to keep the underscores of the names.
+func WithKeyFunc(f KeyFunc) Option {
+	return func(o *options) {
+		o.keyFunc = f
+	}
+}
+
+// DefaultKeyFunc lowercases the name and replaces every underscore with a dot,
+// DB_HOST is mapped onto the key "db.host".
+func DefaultKeyFunc(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "."))
+}
+
+type env struct {
+	opts options
+}
+
+// NewSource new an env source collecting the variables with one of the prefixes,
+// or all the variables without prefixes. The names are mapped onto the nested keys,
+// with the prefix "APP", APP_DB_HOST=localhost is loaded as the key "db.host".
+func NewSource(prefixes ...string) config.Source {
+	return NewSourceWithOptions(WithPrefix(prefixes...))
+}
+
+// NewSourceWithOptions new an env source with options.
+func NewSourceWithOptions(opts ...Option) config.Source {
+	o := options{keyFunc: DefaultKeyFunc}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &env{opts: o}
+}
+
+// Load loads the variables sorted by key, when a variable is also the parent of
+// others, like APP_DB and APP_DB_HOST, the nested keys override it. When the
+// variables of several prefixes map onto the same key, the later prefix overrides
+// the earlier ones, e.g. OTHER_NAME overrides APP_NAME with the prefixes "APP" and "OTHER".
+func (e *env) Load() ([]*config.KeyValue, error) {
+	return e.load(os.Environ()), nil
+}
+
+func (e *env) load(envs []string) []*config.KeyValue {
+	type variable struct {
+		kv     *config.KeyValue
+		prefix int
+	}
+	vars := make([]variable, 0)
+	for _, env := range envs {
+		k, v := env, ""
+		if i := strings.IndexByte(env, '='); i >= 0 {
+			k, v = env[:i], env[i+1:]
+		}
+		key, prefix, ok := e.key(k)
+		if !ok {
+			continue
+		}
+		vars = append(vars, variable{
+			kv:     &config.KeyValue{Key: key, Value: []byte(v)},
+			prefix: prefix,
+		})
+	}
+	sort.SliceStable(vars, func(i, j int) bool {
+		if vars[i].kv.Key != vars[j].kv.Key {
+			return vars[i].kv.Key < vars[j].kv.Key
+		}
+		return vars[i].prefix < vars[j].prefix
+	})
+	kvs := make([]*config.KeyValue, 0, len(vars))
+	for _, v := range vars {
+		kvs = append(kvs, v.kv)
+	}
+	return kvs
+}
+
+// key maps the variable name onto the dotted key and returns the index of its prefix,
+// it reports false if the name has none of the prefixes.
+func (e *env) key(name string) (string, int, bool) {
+	index := -1
+	for i, prefix := range e.opts.prefixes {
+		if rest, ok := trimPrefix(name, prefix); ok {
+			name, index = rest, i
+			break
+		}
+	}
+	if len(e.opts.prefixes) > 0 && index < 0 {
+		return "", 0, false
+	}
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", 0, false
+	}
+	key := e.opts.keyFunc(name)
+	return key, index, key != ""
+}
+
+// trimPrefix trims the prefix at the word boundary, "APP" matches APP_PORT but not APPLE.
+func trimPrefix(name, prefix string) (string, bool) {
+	if !strings.HasPrefix(name, prefix) {
+		return "", false
+	}
+	rest := name[len(prefix):]
+	if strings.HasSuffix(prefix, "_") || rest == "" || rest[0] == '_' {
+		return rest, true
+	}
+	return "", false
+}
+
+func (e *env) Watch() (config.Watcher, error) {
+	return newWatcher(), nil
+}
@@ -0,0 +1,154 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/config"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnv_Load(t *testing.T) {
+	envs := []string{
+		"APP_DB_HOST=localhost",
+		"APP_DB_PORT=5432",
+		"APP_NAME=demo=1",
+		"APPLE=1",
+		"APP_=1",
+		"OTHER_NAME=other",
+		"PATH=/bin",
+	}
+	tests := []struct {
+		prefixes []string
+		want     map[string]string
+	}{
+		{
+			prefixes: []string{"APP"},
+			want:     map[string]string{"db.host": "localhost", "db.port": "5432", "name": "demo=1"},
+		},
+		{
+			// the later prefix overrides the earlier ones
+			prefixes: []string{"APP_", "OTHER_"},
+			want:     map[string]string{"db.host": "localhost", "db.port": "5432", "name": "other"},
+		},
+		{
+			prefixes: []string{"OTHER_", "APP_"},
+			want:     map[string]string{"db.host": "localhost", "db.port": "5432", "name": "demo=1"},
+		},
+		{
+			want: map[string]string{
+				"app.db.host": "localhost", "app.db.port": "5432", "app.name": "demo=1",
+				"apple": "1", "app": "1", "other.name": "other", "path": "/bin",
+			},
+		},
+	}
+	reversed := make([]string, 0, len(envs))
+	for i := len(envs) - 1; i >= 0; i-- {
+		reversed = append(reversed, envs[i])
+	}
+	for _, test := range tests {
+		// the order of the variables does not matter
+		kvs := NewSource(test.prefixes...).(*env).load(envs)
+		if r := NewSource(test.prefixes...).(*env).load(reversed); !reflect.DeepEqual(kvs, r) {
+			t.Errorf("prefixes %v: unstable order %v, %v", test.prefixes, kvs, r)
+		}
+		got := make(map[string]string, len(kvs))
+		for _, kv := range kvs {
+			if kv.Format != "" {
+				t.Errorf("unexpected format %q of %s", kv.Format, kv.Key)
+			}
+			got[kv.Key] = string(kv.Value)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("prefixes %v: got %v, want %v", test.prefixes, got, test.want)
+			continue
+		}
+		for k, v := range test.want {
+			if got[k] != v {
+				t.Errorf("prefixes %v: %s = %q, want %q", test.prefixes, k, got[k], v)
+			}
+		}
+	}
+}
+
+func TestEnv_KeyFunc(t *testing.T) {
+	keyFunc := func(name string) string {
+		if name == "SKIP" {
+			return ""
+		}
+		return strings.ToLower(strings.ReplaceAll(name, "__", "."))
+	}
+	kvs := NewSourceWithOptions(WithPrefix("APP"), WithKeyFunc(keyFunc)).(*env).load([]string{
+		"APP_DB__MAX_CONNS=10",
+		"APP_SKIP=1",
+		"OTHER_NAME=other",
+	})
+	if len(kvs) != 1 || kvs[0].Key != "db.max_conns" || string(kvs[0].Value) != "10" {
+		t.Errorf("unexpected key values: %v", kvs)
+	}
+}
+
+type testSource struct {
+	kvs []*config.KeyValue
+}
+
+func (s *testSource) Load() ([]*config.KeyValue, error) { return s.kvs, nil }
+func (s *testSource) Watch() (config.Watcher, error)    { return newWatcher(), nil }
+
+func TestEnv_Config(t *testing.T) {
+	for k, v := range map[string]string{
+		"KRATOS_TEST_SERVER_HTTP_ADDR": "0.0.0.0:8001",
+		"KRATOS_TEST_DEBUG":            "true",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Unsetenv(k)
+	}
+	file := &testSource{kvs: []*config.KeyValue{{
+		Key:    "config.json",
+		Value:  []byte(`{"server":{"http":{"addr":"0.0.0.0:8000","timeout":"1s"}},"debug":false}`),
+		Format: "json",
+	}}}
+	c := config.New(config.WithSource(file, NewSource("KRATOS_TEST")))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if addr, _ := c.Value("server.http.addr").String(); addr != "0.0.0.0:8001" {
+		t.Errorf("server.http.addr = %q", addr)
+	}
+	if timeout, _ := c.Value("server.http.timeout").Duration(); timeout != time.Second {
+		t.Errorf("server.http.timeout = %v", timeout)
+	}
+	if debug, err := c.Value("debug").Bool(); err != nil || !debug {
+		t.Errorf("debug = %v, %v", debug, err)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	w, err := NewSource().Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Next()
+		done <- err
+	}()
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Next is not unblocked by Stop")
+	}
+}
@@ -0,0 +1,30 @@
+package env
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/config"
+)
+
+var _ config.Watcher = (*watcher)(nil)
+
+// watcher never reports changes, the environment of the process is fixed once started.
+type watcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newWatcher() *watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &watcher{ctx: ctx, cancel: cancel}
+}
+
+// Next blocks until the watcher is stopped.
+func (w *watcher) Next() ([]*config.KeyValue, error) {
+	<-w.ctx.Done()
+	return nil, w.ctx.Err()
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
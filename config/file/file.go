@@ -0,0 +1,105 @@
+package file
+
+import (
+	"github.com/tiennampham23/kratos-cloned/config"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ config.Source = (*file)(nil)
+
+// Option is file source option.
+type Option func(o *options)
+
+type options struct {
+	pollInterval time.Duration
+}
+
+// WithPolling watches the files by polling them at the interval instead of fsnotify,
+// for the file systems without change notifications such as NFS.
+func WithPolling(interval time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = interval
+	}
+}
+
+type file struct {
+	path string
+	opts options
+}
+
+// NewSource new a file source, the path can be a single file or a directory
+// of which every file is loaded.
+func NewSource(path string, opts ...Option) config.Source {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &file{path: path, opts: o}
+}
+
+func (f *file) loadFile(path string) (*config.KeyValue, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(path)
+	return &config.KeyValue{
+		Key:    name,
+		Format: format(name),
+		Value:  data,
+	}, nil
+}
+
+// loadDir loads the regular files of the directory sorted by name, the hidden files
+// and the sub directories, like the "..data" ones of a Kubernetes ConfigMap, are skipped.
+func (f *file) loadDir(path string) ([]*config.KeyValue, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	kvs := make([]*config.KeyValue, 0, len(files))
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		name := filepath.Join(path, file.Name())
+		// follow the symlinks to check the target type
+		info, err := os.Stat(name)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		kv, err := f.loadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs, nil
+}
+
+func (f *file) Load() ([]*config.KeyValue, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return f.loadDir(f.path)
+	}
+	kv, err := f.loadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	return []*config.KeyValue{kv}, nil
+}
+
+func (f *file) Watch() (config.Watcher, error) {
+	return newWatcher(f)
+}
@@ -0,0 +1,236 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/config"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	_testJSON = `{"server":{"addr":"0.0.0.0:8000"}}`
+	_testYAML = "server:\n  timeout: 1s\n"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFile_Load(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.json"), _testJSON)
+	writeFile(t, filepath.Join(dir, "app.yaml"), _testYAML)
+	writeFile(t, filepath.Join(dir, ".hidden.json"), "{}")
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	kvs, err := NewSource(filepath.Join(dir, "app.json")).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvs) != 1 || kvs[0].Key != "app.json" || kvs[0].Format != "json" || string(kvs[0].Value) != _testJSON {
+		t.Errorf("unexpected file key values: %+v", kvs)
+	}
+
+	kvs, err = NewSource(dir).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvs) != 2 || kvs[0].Key != "app.json" || kvs[1].Key != "app.yaml" || kvs[1].Format != "yaml" {
+		t.Errorf("unexpected dir key values: %+v", kvs)
+	}
+
+	if _, err = NewSource(filepath.Join(dir, "none.json")).Load(); !os.IsNotExist(err) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
+
+func TestFile_Config(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.json"), _testJSON)
+	writeFile(t, filepath.Join(dir, "app.yaml"), _testYAML)
+
+	c := config.New(config.WithSource(NewSource(dir)))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if addr, _ := c.Value("server.addr").String(); addr != "0.0.0.0:8000" {
+		t.Errorf("server.addr = %q", addr)
+	}
+	if d, _ := c.Value("server.timeout").Duration(); d != time.Second {
+		t.Errorf("server.timeout = %v", d)
+	}
+
+	changed := make(chan string, 1)
+	if err := c.Watch("server.addr", func(_ string, v config.Value) {
+		s, _ := v.String()
+		changed <- s
+	}); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "app.json"), `{"server":{"addr":"0.0.0.0:8001"}}`)
+	select {
+	case addr := <-changed:
+		if addr != "0.0.0.0:8001" {
+			t.Errorf("server.addr = %q", addr)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for the change")
+	}
+}
+
+func next(t *testing.T, w config.Watcher) []*config.KeyValue {
+	t.Helper()
+	type result struct {
+		kvs []*config.KeyValue
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		kvs, err := w.Next()
+		ch <- result{kvs, err}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		return r.kvs
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for the change")
+	}
+	return nil
+}
+
+func TestWatcher_Write(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"fsnotify": nil,
+		"polling":  {WithPolling(20 * time.Millisecond)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.json")
+			writeFile(t, path, _testJSON)
+
+			w, err := NewSource(path, opts...).Watch()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Stop()
+
+			// a sibling file does not change the source
+			writeFile(t, filepath.Join(dir, "other.json"), "{}")
+			writeFile(t, path, `{"a":1}`)
+			kvs := next(t, w)
+			if len(kvs) != 1 || string(kvs[0].Value) != `{"a":1}` {
+				t.Errorf("unexpected key values: %+v", kvs)
+			}
+		})
+	}
+}
+
+func TestWatcher_RenameSwap(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.json")
+	writeFile(t, path, _testJSON)
+
+	w, err := NewSource(path).Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	for _, data := range []string{`{"a":1}`, `{"a":2}`} {
+		tmp := filepath.Join(dir, ".app.json.swp")
+		writeFile(t, tmp, data)
+		if err = os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+		kvs := next(t, w)
+		if len(kvs) != 1 || string(kvs[0].Value) != data {
+			t.Errorf("unexpected key values: %+v", kvs)
+		}
+	}
+}
+
+// TestWatcher_SymlinkFlip mimics the atomic update of a Kubernetes ConfigMap volume:
+// app.json -> ..data/app.json, ..data -> ..v1, and ..data is replaced to point to ..v2.
+func TestWatcher_SymlinkFlip(t *testing.T) {
+	dir := t.TempDir()
+	for _, v := range []string{"..v1", "..v2"} {
+		if err := os.Mkdir(filepath.Join(dir, v), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(dir, "..v1", "app.json"), _testJSON)
+	writeFile(t, filepath.Join(dir, "..v2", "app.json"), `{"a":1}`)
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "app.json"), filepath.Join(dir, "app.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{dir, filepath.Join(dir, "app.json")} {
+		src := NewSource(path)
+		kvs, err := src.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(kvs) != 1 || string(kvs[0].Value) != _testJSON {
+			t.Fatalf("unexpected key values: %+v", kvs)
+		}
+	}
+
+	w, err := NewSource(dir).Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err = os.Symlink("..v2", tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	kvs := next(t, w)
+	if len(kvs) != 1 || kvs[0].Key != "app.json" || string(kvs[0].Value) != `{"a":1}` {
+		t.Errorf("unexpected key values: %+v", kvs)
+	}
+}
+
+func TestWatcher_Stop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	writeFile(t, path, _testJSON)
+	w, err := NewSource(path).Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Next()
+		done <- err
+	}()
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Next is not unblocked by Stop")
+	}
+}
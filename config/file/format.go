@@ -0,0 +1,11 @@
+package file
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// format returns the format of the file by its extension, "app.yaml" => "yaml".
+func format(name string) string {
+	return strings.TrimPrefix(filepath.Ext(name), ".")
+}
@@ -0,0 +1,160 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"github.com/fsnotify/fsnotify"
+	"github.com/tiennampham23/kratos-cloned/config"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ config.Watcher = (*watcher)(nil)
+
+const (
+	// defaultPollInterval is the polling interval when fsnotify is not available.
+	defaultPollInterval = time.Second
+	// settleDelay is how long the watcher waits for the burst of events of a single
+	// write, such as the rename-swap of an editor, to settle before reloading.
+	settleDelay = 50 * time.Millisecond
+)
+
+type watcher struct {
+	f    *file
+	fw   *fsnotify.Watcher
+	tick *time.Ticker
+	last []*config.KeyValue
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newWatcher watches the directory of the source rather than the files themselves,
+// so the files replaced by a rename, or switched by a symlink flip of a Kubernetes
+// ConfigMap, are still watched. It falls back to polling when fsnotify fails.
+func newWatcher(f *file) (config.Watcher, error) {
+	last, err := f.Load()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &watcher{f: f, last: last, ctx: ctx, cancel: cancel}
+	if f.opts.pollInterval > 0 {
+		w.tick = time.NewTicker(f.opts.pollInterval)
+		return w, nil
+	}
+	if w.fw, err = newFSWatcher(f.path); err != nil {
+		w.tick = time.NewTicker(defaultPollInterval)
+	}
+	return w, nil
+}
+
+func newFSWatcher(path string) (*fsnotify.Watcher, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		path = filepath.Dir(path)
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err = fw.Add(path); err != nil {
+		_ = fw.Close()
+		return nil, err
+	}
+	return fw, nil
+}
+
+// Next returns the key values once their content changes, the events
+// that do not change the content, like the ones of sibling files, are ignored.
+func (w *watcher) Next() ([]*config.KeyValue, error) {
+	for {
+		if err := w.wait(); err != nil {
+			return nil, err
+		}
+		kvs, err := w.f.Load()
+		if err != nil {
+			// the file may be missing in the middle of a rename-swap,
+			// the following event reloads it.
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if equal(w.last, kvs) {
+			continue
+		}
+		w.last = kvs
+		return kvs, nil
+	}
+}
+
+// wait blocks until the next poll or the settled fsnotify events.
+func (w *watcher) wait() error {
+	if w.tick != nil {
+		select {
+		case <-w.tick.C:
+			return nil
+		case <-w.ctx.Done():
+			return w.ctx.Err()
+		}
+	}
+	select {
+	case _, ok := <-w.fw.Events:
+		if !ok {
+			return context.Canceled
+		}
+	case err, ok := <-w.fw.Errors:
+		if !ok {
+			return context.Canceled
+		}
+		return err
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+	timer := time.NewTimer(settleDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case _, ok := <-w.fw.Events:
+			if !ok {
+				return context.Canceled
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(settleDelay)
+		case <-timer.C:
+			return nil
+		case <-w.ctx.Done():
+			return w.ctx.Err()
+		}
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	if w.tick != nil {
+		w.tick.Stop()
+	}
+	if w.fw != nil {
+		return w.fw.Close()
+	}
+	return nil
+}
+
+func equal(a, b []*config.KeyValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || a[i].Format != b[i].Format || !bytes.Equal(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
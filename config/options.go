@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"strings"
+
+	// init the codecs decoding the KeyValue formats.
+	_ "github.com/tiennampham23/kratos-cloned/encoding/json"
+	_ "github.com/tiennampham23/kratos-cloned/encoding/proto"
+	_ "github.com/tiennampham23/kratos-cloned/encoding/xml"
+	_ "github.com/tiennampham23/kratos-cloned/encoding/yaml"
+)
+
+// Decoder is config decoder, it decodes the KeyValue into the target map.
+type Decoder func(*KeyValue, map[string]interface{}) error
+
+// Option is config option.
+type Option func(*options)
+
+// Resolver resolves the placeholders of the merged values in place.
+type Resolver func(map[string]interface{}) error
+
+type options struct {
+	sources  []Source
+	decoder  Decoder
+	resolver Resolver
+}
+
+// WithSource with config source, the later sources override the earlier ones.
+func WithSource(s ...Source) Option {
+	return func(o *options) {
+		o.sources = s
+	}
+}
+
+// WithDecoder with config decoder.
+// DefaultDecoder behavior:
+// If KeyValue.Format is non-empty, then KeyValue.Value will be deserialized into map[string]interface{}
+// and stored in the config cache(map[string]interface{})
+// if KeyValue.Format is empty,{KeyValue.Key : KeyValue.Value} will be stored in config cache(map[string]interface{})
+func WithDecoder(d Decoder) Option {
+	return func(o *options) {
+		o.decoder = d
+	}
+}
+
+// WithResolver with config resolver, it replaces the default resolver expanding
+// the ${key:default} placeholders, a nil resolver disables the resolution.
+func WithResolver(r Resolver) Option {
+	return func(o *options) {
+		o.resolver = r
+	}
+}
+
+// defaultDecoder decodes the value by the codec of KeyValue.Format, the values
+// without format are stored as strings under their dotted key path.
+func defaultDecoder(src *KeyValue, target map[string]interface{}) error {
+	if src.Format == "" {
+		// expand key "aaa.bbb" into map[aaa]map[bbb]interface{}
+		keys := strings.Split(src.Key, ".")
+		for i, k := range keys {
+			if i == len(keys)-1 {
+				target[k] = string(src.Value)
+				break
+			}
+			sub, ok := target[k].(map[string]interface{})
+			if !ok {
+				sub = make(map[string]interface{})
+				target[k] = sub
+			}
+			target = sub
+		}
+		return nil
+	}
+	format := strings.ToLower(src.Format)
+	if format == "yml" {
+		format = "yaml"
+	}
+	if codec := encoding.GetCodec(format); codec != nil {
+		return codec.Unmarshal(src.Value, &target)
+	}
+	return fmt.Errorf("unsupported key: %s format: %s", src.Key, src.Format)
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"github.com/tiennampham23/kratos-cloned/encoding/json"
+	"strings"
+	"sync"
+)
+
+// Reader is config reader.
+type Reader interface {
+	Reset(...*KeyValue) error
+	Value(string) (Value, bool)
+	Source() ([]byte, error)
+}
+
+type reader struct {
+	opts   options
+	values map[string]interface{}
+	lock   sync.RWMutex
+}
+
+func newReader(opts options) Reader {
+	return &reader{
+		opts:   opts,
+		values: make(map[string]interface{}),
+	}
+}
+
+// Reset rebuilds and resolves the values from the key values alone and swaps them in at once,
+// the keys no longer present in kvs are dropped.
+func (r *reader) Reset(kvs ...*KeyValue) error {
+	values := make(map[string]interface{})
+	if err := r.merge(values, kvs); err != nil {
+		return err
+	}
+	r.lock.Lock()
+	r.values = values
+	r.lock.Unlock()
+	return nil
+}
+
+func (r *reader) merge(dst map[string]interface{}, kvs []*KeyValue) error {
+	for _, kv := range kvs {
+		next := make(map[string]interface{})
+		if err := r.opts.decoder(kv, next); err != nil {
+			return fmt.Errorf("failed to config decode error: %v key: %s value: %s", err, kv.Key, string(kv.Value))
+		}
+		mergeMap(dst, convertMap(next).(map[string]interface{}))
+	}
+	if r.opts.resolver != nil {
+		if err := r.opts.resolver(dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reader) Value(path string) (Value, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return readValue(r.values, path)
+}
+
+func (r *reader) Source() ([]byte, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return marshalJSON(r.values)
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	return encoding.GetCodec(json.Name).Marshal(v)
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	return encoding.GetCodec(json.Name).Unmarshal(data, v)
+}
+
+// mergeMap deep merges src into dst, the nested maps are merged and the other values of src override dst.
+func mergeMap(dst, src map[string]interface{}) {
+	for k, v := range src {
+		sm, ok := v.(map[string]interface{})
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dm, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dm = make(map[string]interface{}, len(sm))
+			dst[k] = dm
+		}
+		mergeMap(dm, sm)
+	}
+}
+
+// convertMap converts the map[interface{}]interface{} decoded by some codecs into map[string]interface{}.
+func convertMap(src interface{}) interface{} {
+	switch m := src.(type) {
+	case map[string]interface{}:
+		dst := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			dst[k] = convertMap(v)
+		}
+		return dst
+	case map[interface{}]interface{}:
+		dst := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			dst[fmt.Sprint(k)] = convertMap(v)
+		}
+		return dst
+	case []interface{}:
+		dst := make([]interface{}, len(m))
+		for i, v := range m {
+			dst[i] = convertMap(v)
+		}
+		return dst
+	default:
+		return src
+	}
+}
+
+// readValue read Value in given map[string]interface{}
+// by the given path, will return false if not found.
+func readValue(values map[string]interface{}, path string) (Value, bool) {
+	var (
+		next = values
+		keys = strings.Split(path, ".")
+		last = len(keys) - 1
+	)
+	for idx, key := range keys {
+		value, ok := next[key]
+		if !ok {
+			return nil, false
+		}
+		if idx == last {
+			// a null value is present but left unset, atomic.Value cannot store nil
+			av := &atomicValue{}
+			if value != nil {
+				av.Store(value)
+			}
+			return av, true
+		}
+		switch vm := value.(type) {
+		case map[string]interface{}:
+			next = vm
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultResolver expands the ${name} and ${name:default} placeholders of the string values.
+// The name is looked up as a dotted key of the values first and then as an environment variable,
+// the default after the first colon is used when both are missing. The referenced values are
+// resolved recursively, and a value made of a single placeholder keeps the type of the referenced one.
+func defaultResolver(values map[string]interface{}) error {
+	r := &resolver{
+		values:    values,
+		resolved:  make(map[string]interface{}),
+		resolving: make(map[string]bool),
+	}
+	_, err := r.resolve("", values)
+	return err
+}
+
+type resolver struct {
+	values map[string]interface{}
+	// resolved caches the resolved values by key.
+	resolved map[string]interface{}
+	// resolving and stack are the keys being resolved, to detect the cycles.
+	resolving map[string]bool
+	stack     []string
+}
+
+func (r *resolver) resolve(key string, value interface{}) (interface{}, error) {
+	if v, ok := r.resolved[key]; ok && key != "" {
+		return v, nil
+	}
+	if r.resolving[key] {
+		return nil, fmt.Errorf("config: placeholder cycle detected: %s -> %s", strings.Join(r.stack, " -> "), key)
+	}
+	r.resolving[key] = true
+	r.stack = append(r.stack, key)
+	defer func() {
+		delete(r.resolving, key)
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	var err error
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			if v[k], err = r.resolve(joinKey(key, k), sub); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, sub := range v {
+			if v[i], err = r.resolve(fmt.Sprintf("%s[%d]", key, i), sub); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		if value, err = r.expand(key, v); err != nil {
+			return nil, err
+		}
+	}
+	r.resolved[key] = value
+	return value, nil
+}
+
+// expand expands the placeholders of s, which is the value of key.
+func (r *resolver) expand(key, s string) (interface{}, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := closingBrace(s, start+2)
+		if end < 0 {
+			break
+		}
+		value, err := r.placeholder(key, s[start+2:end])
+		if err != nil {
+			return nil, err
+		}
+		// keep the type of the referenced value for a single placeholder
+		if start == 0 && end == len(s)-1 && b.Len() == 0 {
+			return value, nil
+		}
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("config: placeholder ${%s} of key %s refers to a non-scalar value", s[start+2:end], key)
+		}
+		b.WriteString(s[:start])
+		b.WriteString(fmt.Sprint(value))
+		s = s[end+1:]
+	}
+	if b.Len() == 0 {
+		return s, nil
+	}
+	b.WriteString(s)
+	return b.String(), nil
+}
+
+// placeholder resolves the content between the braces of a placeholder.
+func (r *resolver) placeholder(key, content string) (interface{}, error) {
+	name, def, hasDef := content, "", false
+	if i := strings.IndexByte(content, ':'); i >= 0 {
+		name, def, hasDef = content[:i], content[i+1:], true
+	}
+	name = strings.TrimSpace(name)
+	if raw, ok := lookup(r.values, name); ok {
+		return r.resolve(name, raw)
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		return v, nil
+	}
+	if hasDef {
+		return r.expand(key, def)
+	}
+	return nil, fmt.Errorf("config: failed to resolve placeholder ${%s} of key %s: not found", name, key)
+}
+
+// closingBrace returns the index of the brace closing the placeholder started before i, or -1.
+func closingBrace(s string, i int) int {
+	depth := 1
+	for ; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func lookup(values map[string]interface{}, key string) (interface{}, bool) {
+	if key == "" {
+		return nil, false
+	}
+	var cur interface{} = values
+	for _, k := range strings.Split(key, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
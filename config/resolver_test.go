@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultResolver(t *testing.T) {
+	if err := os.Setenv("KRATOS_TEST_DB_HOST", "db.local"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("KRATOS_TEST_DB_HOST")
+
+	values := map[string]interface{}{
+		"server": map[string]interface{}{
+			"host": "${KRATOS_TEST_HOST:0.0.0.0}",
+			"port": 8000.0,
+			"addr": "${server.host}:${server.port}",
+			"url":  "http://${server.addr}/",
+		},
+		"db": map[string]interface{}{
+			"host":   "${KRATOS_TEST_DB_HOST:localhost}",
+			"port":   "${KRATOS_TEST_DB_PORT:5432}",
+			"dsn":    "${db.user:${app.name}}@${db.host}:${db.port}",
+			"unset":  "${KRATOS_TEST_NONE:}",
+			"http":   "${server}",
+			"ports":  []interface{}{"${server.port}", "${db.port}"},
+			"remain": "${not closed",
+		},
+		"app": map[string]interface{}{
+			"name": "demo",
+			"port": "${server.port}",
+		},
+	}
+	if err := defaultResolver(values); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]interface{}{
+		"server.host": "0.0.0.0",
+		"server.addr": "0.0.0.0:8000",
+		"server.url":  "http://0.0.0.0:8000/",
+		"db.host":     "db.local",
+		"db.port":     "5432",
+		"db.dsn":      "demo@db.local:5432",
+		"db.unset":    "",
+		"db.remain":   "${not closed",
+		"db.ports":    []interface{}{8000.0, "5432"},
+		"app.port":    8000.0,
+	}
+	for key, want := range tests {
+		got, _ := lookup(values, key)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %#v, want %#v", key, got, want)
+		}
+	}
+	if addr, _ := lookup(values, "db.http.addr"); addr != "0.0.0.0:8000" {
+		t.Errorf("db.http.addr = %#v", addr)
+	}
+}
+
+func TestDefaultResolver_Error(t *testing.T) {
+	tests := []struct {
+		values map[string]interface{}
+		err    string
+	}{
+		{
+			values: map[string]interface{}{"a": "${b}", "b": "${c}", "c": "${a}"},
+			err:    "cycle detected",
+		},
+		{
+			values: map[string]interface{}{"a": map[string]interface{}{"b": "x${a}"}},
+			err:    "cycle detected",
+		},
+		{
+			values: map[string]interface{}{"a": "${KRATOS_TEST_NONE}"},
+			err:    "${KRATOS_TEST_NONE} of key a: not found",
+		},
+		{
+			values: map[string]interface{}{"a": "x${b}", "b": map[string]interface{}{"c": "1"}},
+			err:    "non-scalar",
+		},
+	}
+	for _, test := range tests {
+		err := defaultResolver(test.values)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("expected error containing %q, got %v", test.err, err)
+		}
+	}
+}
+
+func TestConfig_Resolver(t *testing.T) {
+	src := &testSource{kvs: []*KeyValue{
+		{Key: "config.json", Value: []byte(`{"addr":"${host}:8000"}`), Format: "json"},
+		{Key: "host", Value: []byte("127.0.0.1")},
+	}}
+	c := New(WithSource(src))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if addr, _ := c.Value("addr").String(); addr != "127.0.0.1:8000" {
+		t.Errorf("addr = %q", addr)
+	}
+
+	called := false
+	c = New(WithSource(src), WithResolver(func(values map[string]interface{}) error {
+		called = true
+		values["addr"] = "custom"
+		return nil
+	}))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if addr, _ := c.Value("addr").String(); !called || addr != "custom" {
+		t.Errorf("addr = %q, called = %v", addr, called)
+	}
+
+	c = New(WithSource(&testSource{kvs: []*KeyValue{{Key: "a", Value: []byte("${a}")}}}))
+	if err := c.Load(); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
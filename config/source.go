@@ -8,6 +8,8 @@ type KeyValue struct {
 }
 
 // Watcher watches a source for changes.
+// Next blocks until the source changes and returns all its key values,
+// not only the changed ones.
 type Watcher interface {
 	Next() ([]*KeyValue, error)
 	Stop() error
@@ -18,4 +20,3 @@ type Source interface {
 	Load() ([]*KeyValue, error)
 	Watch() (Watcher, error)
 }
-
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var _ Value = (*atomicValue)(nil)
+
+// Value is config value interface.
+type Value interface {
+	Bool() (bool, error)
+	Int() (int64, error)
+	Float() (float64, error)
+	String() (string, error)
+	Duration() (time.Duration, error)
+	Slice() ([]Value, error)
+	Map() (map[string]Value, error)
+	Scan(interface{}) error
+	Load() interface{}
+	Store(interface{})
+}
+
+type atomicValue struct {
+	atomic.Value
+}
+
+func (v *atomicValue) typeAssertError() error {
+	return fmt.Errorf("%w: %v", ErrTypeAssert, reflect.TypeOf(v.Load()))
+}
+
+func (v *atomicValue) Bool() (bool, error) {
+	switch val := v.Load().(type) {
+	case bool:
+		return val, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, string:
+		return strconv.ParseBool(fmt.Sprint(val))
+	case []byte:
+		return strconv.ParseBool(string(val))
+	}
+	return false, v.typeAssertError()
+}
+
+func (v *atomicValue) Int() (int64, error) {
+	switch val := v.Load().(type) {
+	case int:
+		return int64(val), nil
+	case int8:
+		return int64(val), nil
+	case int16:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case uint:
+		return int64(val), nil
+	case uint8:
+		return int64(val), nil
+	case uint16:
+		return int64(val), nil
+	case uint32:
+		return int64(val), nil
+	case uint64:
+		return int64(val), nil
+	case float32:
+		return floatToInt(float64(val))
+	case float64:
+		return floatToInt(val)
+	case string:
+		return strconv.ParseInt(val, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(val), 10, 64)
+	}
+	return 0, v.typeAssertError()
+}
+
+// floatToInt converts the integral floats decoded from the JSON numbers, a fraction is an error.
+func floatToInt(f float64) (int64, error) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %v is not an integer", ErrTypeAssert, f)
+	}
+	return int64(f), nil
+}
+
+func (v *atomicValue) Float() (float64, error) {
+	switch val := v.Load().(type) {
+	case float32:
+		return float64(val), nil
+	case float64:
+		return val, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return strconv.ParseFloat(fmt.Sprint(val), 64)
+	case string:
+		return strconv.ParseFloat(val, 64)
+	case []byte:
+		return strconv.ParseFloat(string(val), 64)
+	}
+	return 0.0, v.typeAssertError()
+}
+
+func (v *atomicValue) String() (string, error) {
+	switch val := v.Load().(type) {
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case fmt.Stringer:
+		return val.String(), nil
+	}
+	return "", v.typeAssertError()
+}
+
+// Duration accepts both the time.ParseDuration format ("1.5s") and an integer of nanoseconds.
+func (v *atomicValue) Duration() (time.Duration, error) {
+	switch val := v.Load().(type) {
+	case string:
+		if d, err := time.ParseDuration(val); err == nil {
+			return d, nil
+		}
+	case []byte:
+		if d, err := time.ParseDuration(string(val)); err == nil {
+			return d, nil
+		}
+	}
+	val, err := v.Int()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(val), nil
+}
+
+func (v *atomicValue) Slice() ([]Value, error) {
+	vals, ok := v.Load().([]interface{})
+	if !ok {
+		return nil, v.typeAssertError()
+	}
+	slices := make([]Value, 0, len(vals))
+	for _, val := range vals {
+		a := &atomicValue{}
+		a.Store(val)
+		slices = append(slices, a)
+	}
+	return slices, nil
+}
+
+func (v *atomicValue) Map() (map[string]Value, error) {
+	vals, ok := v.Load().(map[string]interface{})
+	if !ok {
+		return nil, v.typeAssertError()
+	}
+	m := make(map[string]Value, len(vals))
+	for key, val := range vals {
+		a := &atomicValue{}
+		a.Store(val)
+		m[key] = a
+	}
+	return m, nil
+}
+
+// Scan decodes the value into obj through its json representation.
+func (v *atomicValue) Scan(obj interface{}) error {
+	data, err := marshalJSON(v.Load())
+	if err != nil {
+		return err
+	}
+	return unmarshalJSON(data, obj)
+}
+
+type errValue struct {
+	err error
+}
+
+func (v errValue) Bool() (bool, error)              { return false, v.err }
+func (v errValue) Int() (int64, error)              { return 0, v.err }
+func (v errValue) Float() (float64, error)          { return 0.0, v.err }
+func (v errValue) Duration() (time.Duration, error) { return 0, v.err }
+func (v errValue) String() (string, error)          { return "", v.err }
+func (v errValue) Scan(interface{}) error           { return v.err }
+func (v errValue) Load() interface{}                { return nil }
+func (v errValue) Store(interface{})                {}
+func (v errValue) Slice() ([]Value, error)          { return nil, v.err }
+func (v errValue) Map() (map[string]Value, error)   { return nil, v.err }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func newValue(v interface{}) Value {
+	av := &atomicValue{}
+	av.Store(v)
+	return av
+}
+
+func TestAtomicValue(t *testing.T) {
+	for _, v := range []interface{}{int(1), int32(1), int64(1), float64(1), "1", []byte("1")} {
+		if i, err := newValue(v).Int(); err != nil || i != 1 {
+			t.Errorf("Int(%#v) = %d, %v", v, i, err)
+		}
+		if f, err := newValue(v).Float(); err != nil || f != 1 {
+			t.Errorf("Float(%#v) = %v, %v", v, f, err)
+		}
+		if b, err := newValue(v).Bool(); err != nil || !b {
+			t.Errorf("Bool(%#v) = %v, %v", v, b, err)
+		}
+		if s, err := newValue(v).String(); err != nil || s != "1" {
+			t.Errorf("String(%#v) = %q, %v", v, s, err)
+		}
+	}
+	for _, v := range []interface{}{"2s", int64(2 * time.Second), "2000000000"} {
+		if d, err := newValue(v).Duration(); err != nil || d != 2*time.Second {
+			t.Errorf("Duration(%#v) = %v, %v", v, d, err)
+		}
+	}
+	if _, err := newValue("abc").Int(); err == nil {
+		t.Error("expected Int error")
+	}
+	for _, v := range []interface{}{float64(1.5), float32(-0.5), float64(1e20)} {
+		if i, err := newValue(v).Int(); err == nil {
+			t.Errorf("Int(%#v) = %d, expected error", v, i)
+		}
+	}
+	if _, err := newValue(map[string]interface{}{}).Slice(); err == nil {
+		t.Error("expected Slice error")
+	}
+	if _, err := newValue([]interface{}{}).Map(); err == nil {
+		t.Error("expected Map error")
+	}
+}
+
+func TestReader_Reset(t *testing.T) {
+	r := newReader(options{decoder: defaultDecoder})
+	if err := r.Reset(
+		&KeyValue{Key: "a", Value: []byte(`{"a":{"b":1,"c":[1,2]}}`), Format: "json"},
+		&KeyValue{Key: "a.d", Value: []byte("x")},
+		&KeyValue{Key: "b", Value: []byte("a:\n  c: [3]\n"), Format: "yaml"},
+	); err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"a":{"b":1,"c":[3],"d":"x"}}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	// a failed reset leaves the values untouched
+	if err := r.Reset(&KeyValue{Key: "a.d", Value: []byte("y")}, &KeyValue{Key: "bad", Value: []byte("{"), Format: "json"}); err == nil {
+		t.Fatal("expected reset error")
+	}
+	if v, ok := r.Value("a.d"); !ok {
+		t.Error("a.d not found")
+	} else if s, _ := v.String(); s != "x" {
+		t.Errorf("a.d = %q, want x", s)
+	}
+}
@@ -2,17 +2,18 @@ package consul
 
 import (
 	"context"
-	"errors"
 	"github.com/hashicorp/consul/api"
 	"github.com/tiennampham23/kratos-cloned/config"
+	"github.com/tiennampham23/kratos-cloned/errors"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 )
 
 type Option func(o *options)
 
 type options struct {
-	ctx context.Context
+	ctx  context.Context
 	path string
 }
 
@@ -29,49 +30,55 @@ func WithPath(path string) Option {
 }
 
 type source struct {
-	client *api.Client
+	client  *api.Client
 	options *options
+	// index is the KV index of the last Load, the watcher delivers the changes after it.
+	index uint64
 }
 
 func New(client *api.Client, opts ...Option) (config.Source, error) {
 	options := &options{
-		ctx: context.Background(),
+		ctx:  context.Background(),
 		path: "",
 	}
 	for _, opt := range opts {
 		opt(options)
 	}
 	if options.path == "" {
-		return nil, errors.New("path invalid")
+		return nil, errors.BadRequest("PATH_INVALID", "path invalid")
 	}
 	return &source{
-		client: client,
+		client:  client,
 		options: options,
 	}, nil
 }
 
-
 func (s *source) Load() ([]*config.KeyValue, error) {
-	kv, _, err := s.client.KV().List(s.options.path, nil)
+	kv, meta, err := s.client.KV().List(s.options.path, nil)
 	if err != nil {
 		return nil, err
 	}
+	atomic.StoreUint64(&s.index, meta.LastIndex)
+	return s.convert(kv), nil
+}
+
+func (s *source) convert(kv api.KVPairs) []*config.KeyValue {
 	pathPrefix := s.options.path
 	if !strings.HasSuffix(s.options.path, "/") {
 		pathPrefix = pathPrefix + "/"
 	}
-	kvs := make([]*config.KeyValue, 0)
+	kvs := make([]*config.KeyValue, 0, len(kv))
 	for _, item := range kv {
 		k := strings.TrimPrefix(item.Key, pathPrefix)
 		kvs = append(kvs, &config.KeyValue{
-			Key: k,
-			Value: item.Value,
+			Key:    k,
+			Value:  item.Value,
 			Format: strings.TrimPrefix(filepath.Ext(k), "."),
 		})
 	}
-	return kvs, nil
+	return kvs
 }
 
 func (s *source) Watch() (config.Watcher, error) {
 	return newWatcher(s)
-}
\ No newline at end of file
+}
@@ -1,20 +1,149 @@
 package consul
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/hashicorp/consul/api"
+	"github.com/tiennampham23/kratos-cloned/config"
+	"github.com/tiennampham23/kratos-cloned/errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 const testKey = "kratos/test/config/key"
 const testPath = "kratos/test/config"
 
-func TestConfig(t *testing.T) {
-	client, err := api.NewClient(&api.Config{
-		Address: "127.0.0.1:8500",
-	})
+// fakeConsul is a stand-in of the Consul KV API supporting the blocking queries.
+type fakeConsul struct {
+	lock    sync.Mutex
+	index   uint64
+	kvs     map[string][]byte
+	changed chan struct{}
+	fail    bool
+}
+
+func newFakeConsul(t *testing.T) (*fakeConsul, *api.Client) {
+	f := &fakeConsul{index: 1, kvs: make(map[string][]byte), changed: make(chan struct{})}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client, err := api.NewClient(&api.Config{Address: strings.TrimPrefix(srv.URL, "http://")})
 	if err != nil {
 		t.Fatal(err)
 	}
+	return f, client
+}
+
+func (f *fakeConsul) setFail(fail bool) {
+	f.lock.Lock()
+	f.fail = fail
+	f.lock.Unlock()
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/v1/kv/") {
+		http.NotFound(w, r)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := ioutil.ReadAll(r.Body)
+		f.update(func() { f.kvs[key] = data })
+		_, _ = w.Write([]byte("true"))
+	case http.MethodDelete:
+		f.update(func() { delete(f.kvs, key) })
+		_, _ = w.Write([]byte("true"))
+	case http.MethodGet:
+		f.list(w, r, key)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeConsul) update(fn func()) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	fn()
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsul) list(w http.ResponseWriter, r *http.Request, prefix string) {
+	f.lock.Lock()
+	index, changed := f.index, f.changed
+	f.lock.Unlock()
+	if wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); wait >= index {
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.fail {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	pairs := make(api.KVPairs, 0)
+	for k, v := range f.kvs {
+		if strings.HasPrefix(k, prefix) {
+			pairs = append(pairs, &api.KVPair{Key: k, Value: v, ModifyIndex: f.index})
+		}
+	}
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+type nextResult struct {
+	kvs []*config.KeyValue
+	err error
+}
+
+func asyncNext(w config.Watcher) <-chan nextResult {
+	ch := make(chan nextResult, 1)
+	go func() {
+		kvs, err := w.Next()
+		ch <- nextResult{kvs, err}
+	}()
+	return ch
+}
+
+func waitNext(t *testing.T, ch <-chan nextResult) nextResult {
+	t.Helper()
+	select {
+	case r := <-ch:
+		return r
+	// the plan retries a failed query after 5 seconds
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for Next")
+	}
+	return nextResult{}
+}
+
+func TestNew_PathInvalid(t *testing.T) {
+	if _, err := New(nil); !errors.IsBadRequest(err) {
+		t.Errorf("expected bad request error, got %v", err)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	_, client := newFakeConsul(t)
 	if _, err := client.KV().Put(&api.KVPair{Key: testKey, Value: []byte("test config")}, nil); err != nil {
 		t.Fatal(err)
 	}
@@ -38,16 +167,25 @@ func TestConfig(t *testing.T) {
 		_ = w.Stop()
 	}()
 
+	// the unchanged KV index of the first query is not delivered
+	next := asyncNext(w)
+	select {
+	case r := <-next:
+		t.Fatalf("unexpected change: %+v", r)
+	case <-time.After(200 * time.Millisecond):
+	}
+
 	if _, err = client.KV().Put(&api.KVPair{
 		Key:   testKey,
 		Value: []byte("new config"),
 	}, nil); err != nil {
 		t.Error(err)
 	}
-	if kvs, err = w.Next(); err != nil {
-		t.Fatal(err)
+	r := waitNext(t, next)
+	if r.err != nil {
+		t.Fatal(r.err)
 	}
-
+	kvs = r.kvs
 	if len(kvs) != 1 || kvs[0].Key != "key" || string(kvs[0].Value) != "new config" {
 		t.Fatal("config error")
 	}
@@ -55,4 +193,64 @@ func TestConfig(t *testing.T) {
 	if _, err := client.KV().Delete(testKey, nil); err != nil {
 		t.Error(err)
 	}
+	r = waitNext(t, asyncNext(w))
+	if r.err != nil || len(r.kvs) != 0 {
+		t.Fatalf("unexpected result after delete: %+v", r)
+	}
+}
+
+func TestWatcher_Error(t *testing.T) {
+	f, client := newFakeConsul(t)
+	source, err := New(client, WithPath(testPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = source.Load(); err != nil {
+		t.Fatal(err)
+	}
+	w, err := source.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	f.setFail(true)
+	if _, err = client.KV().Put(&api.KVPair{Key: testKey, Value: []byte("v1")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if r := waitNext(t, asyncNext(w)); r.err == nil {
+		t.Fatalf("expected the query error, got %+v", r)
+	}
+
+	// the watcher recovers once the queries succeed again
+	f.setFail(false)
+	r := waitNext(t, asyncNext(w))
+	if r.err != nil || len(r.kvs) != 1 || string(r.kvs[0].Value) != "v1" {
+		t.Fatalf("unexpected result after recovery: %+v", r)
+	}
+}
+
+func TestWatcher_Stop(t *testing.T) {
+	_, client := newFakeConsul(t)
+	source, err := New(client, WithPath(testPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := source.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := asyncNext(w)
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if r := waitNext(t, next); !errors.Is(r.err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %+v", r)
+	}
+	if _, err = w.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
 }
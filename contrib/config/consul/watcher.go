@@ -1,71 +1,138 @@
 package consul
 
 import (
+	"context"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/api/watch"
 	"github.com/tiennampham23/kratos-cloned/config"
+	"sync"
+	"sync/atomic"
 )
 
 type watcher struct {
 	source *source
-	ch chan interface{}
-	closeChan chan struct{}
-	wp *watch.Plan
-}
+	wp     *watch.Plan
+	// index is the KV index of the last delivered key values.
+	index uint64
 
-func (w *watcher) handle(idx uint64, data interface{}) {
-	if data != nil {
-		return
-	}
-	_, ok := data.(api.KVPairs)
-	if !ok {
-		return
-	}
-	w.ch <- struct{}{}
+	lock    sync.Mutex
+	kvs     []*config.KeyValue
+	pending bool
+	err     error
+	notify  chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
 }
 
 func newWatcher(s *source) (*watcher, error) {
-	w := &watcher{
-		source: s,
-		ch: make(chan interface{}),
-		closeChan: make(chan struct{}),
-	}
 	wp, err := watch.Parse(map[string]interface{}{
-		"type": "keyprefix",
+		"type":   "keyprefix",
 		"prefix": s.options.path,
 	})
 	if err != nil {
 		return nil, err
 	}
+	ctx, cancel := context.WithCancel(s.options.ctx)
+	w := &watcher{
+		source: s,
+		wp:     wp,
+		index:  atomic.LoadUint64(&s.index),
+		notify: make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	// the plan retries the failed queries by itself, wrap its watcher
+	// to report the errors through Next as well.
+	fetch := wp.Watcher
+	wp.Watcher = func(p *watch.Plan) (watch.BlockingParamVal, interface{}, error) {
+		idx, data, err := fetch(p)
+		if err != nil && w.ctx.Err() == nil {
+			w.publish(nil, err)
+		}
+		return idx, data, err
+	}
 	wp.Handler = w.handle
-	w.wp = wp
-	
+
 	// wp.Run is a blocking call and will prevent newWatcher from returning
 	go func() {
-		err := wp.RunWithClientAndHclog(s.client, nil)
-		if err != nil {
-			panic(err)
+		if err := wp.RunWithClientAndHclog(s.client, nil); err != nil {
+			w.publish(nil, err)
 		}
 	}()
-	
+	go func() {
+		<-ctx.Done()
+		wp.Stop()
+	}()
 	return w, nil
 }
 
-func (w *watcher) Next() ([]*config.KeyValue, error) {
+// handle is called by the plan with the KV index and the key pairs of the prefix,
+// only the index different from the delivered one is a change.
+func (w *watcher) handle(idx uint64, data interface{}) {
+	kv, ok := data.(api.KVPairs)
+	if !ok {
+		return
+	}
+	if idx == w.index {
+		return
+	}
+	w.index = idx
+	w.publish(w.source.convert(kv), nil)
+}
+
+// publish keeps the latest key values and error for Next without blocking the plan,
+// a change supersedes the error published before it.
+func (w *watcher) publish(kvs []*config.KeyValue, err error) {
+	w.lock.Lock()
+	if err != nil {
+		w.err = err
+	} else {
+		w.kvs, w.pending, w.err = kvs, true, nil
+	}
+	w.lock.Unlock()
+	w.signal()
+}
+
+func (w *watcher) signal() {
 	select {
-	case _, ok := <- w.ch:
-		if !ok {
-			return nil, nil
-		}
-		return w.source.Load()
-	case <- w.closeChan:
-		return nil, nil
+	case w.notify <- struct{}{}:
+	default:
 	}
 }
 
+// Next returns the key values of the prefix once its KV index changes, the errors
+// of the plan, or the error of the context once the watcher is stopped.
+func (w *watcher) Next() ([]*config.KeyValue, error) {
+	for {
+		select {
+		case <-w.notify:
+		case <-w.ctx.Done():
+		}
+		if err := w.ctx.Err(); err != nil {
+			return nil, err
+		}
+		w.lock.Lock()
+		if w.pending {
+			kvs := w.kvs
+			w.kvs, w.pending = nil, false
+			if w.err != nil {
+				w.signal()
+			}
+			w.lock.Unlock()
+			return kvs, nil
+		}
+		err := w.err
+		w.err = nil
+		w.lock.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
 
 func (w *watcher) Stop() error {
-	w.wp.Stop()
-	close(w.closeChan)
+	w.once.Do(w.cancel)
 	return nil
-}
\ No newline at end of file
+}
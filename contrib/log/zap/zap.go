@@ -4,47 +4,194 @@ import (
 	"fmt"
 	"github.com/tiennampham23/kratos-cloned/log"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"reflect"
+	"runtime"
+	"strings"
 )
 
+var _ log.Logger = (*Logger)(nil)
+
+// Logger is a log.Logger adapter of zap.
 type Logger struct {
 	log *zap.Logger
 }
 
-func NewLogger(zLog *zap.Logger) *Logger  {
+// Option is the option of the zap logger built by New.
+type Option func(*options)
+
+type options struct {
+	development bool
+	level       *log.Level
+	encoding    string
+	outputPaths []string
+	zapOpts     []zap.Option
+}
+
+// WithDevelopment builds a development logger, which logs at debug level in
+// the console encoding with stack traces from warn level, instead of a production one.
+func WithDevelopment() Option {
+	return func(o *options) {
+		o.development = true
+	}
+}
+
+// WithLevel with the minimum level, info for production and debug for development by default.
+func WithLevel(level log.Level) Option {
+	return func(o *options) {
+		o.level = &level
+	}
+}
+
+// WithEncoding with the encoding, "json" or "console".
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		o.encoding = encoding
+	}
+}
+
+// WithOutputPaths with the output paths, stderr by default.
+func WithOutputPaths(paths ...string) Option {
+	return func(o *options) {
+		o.outputPaths = paths
+	}
+}
+
+// WithZapOptions with the options passed to the zap config build.
+func WithZapOptions(opts ...zap.Option) Option {
+	return func(o *options) {
+		o.zapOpts = append(o.zapOpts, opts...)
+	}
+}
+
+// New builds a production zap logger, or a development one WithDevelopment,
+// and wraps it as a log.Logger, which reports the caller of the log package.
+func New(opts ...Option) (*Logger, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cfg := zap.NewProductionConfig()
+	if o.development {
+		cfg = zap.NewDevelopmentConfig()
+	}
+	if o.level != nil {
+		cfg.Level = zap.NewAtomicLevelAt(zapLevel(*o.level))
+	}
+	if o.encoding != "" {
+		cfg.Encoding = o.encoding
+	}
+	if len(o.outputPaths) > 0 {
+		cfg.OutputPaths = o.outputPaths
+	}
+	zLog, err := cfg.Build(o.zapOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogger(zLog), nil
+}
+
+// NewLogger wraps the zap logger as a log.Logger.
+func NewLogger(zLog *zap.Logger) *Logger {
 	return &Logger{
 		log: zLog,
 	}
 }
 
-
-func (l *Logger) Log(level log.Level, kv ...interface{})  error {
-	if len(kv) == 0 && len(kv) %2 != 0 {
-		l.log.Warn(fmt.Sprintf("Key values must appear in pairs: %v", kv))
+// Log logs the kvs at the zap level matching the level, or info for an unknown
+// level, the value of the log.DefaultMessageKey key is logged as the zap message.
+// The fatal logs follow the zap fatal action, which exits the process unless
+// changed by zap.OnFatal.
+//
+// When the zap logger reports the caller, it is the first frame outside the log
+// package and this adapter, whether it is logged by a log.Helper, the global
+// log functions or a logger of log.With.
+func (l *Logger) Log(level log.Level, kv ...interface{}) error {
+	if len(kv) == 0 || len(kv)%2 != 0 {
+		l.write(zapcore.WarnLevel, fmt.Sprint("Key values must appear in pairs: ", kv))
 		return nil
 	}
-	var data []zap.Field
+	var (
+		msg  string
+		data = make([]zap.Field, 0, len(kv)/2)
+	)
 	for i := 0; i < len(kv); i += 2 {
-		data = append(data, zap.Any(fmt.Sprint(kv[i]), kv[i + 1]))
+		if kv[i] == log.DefaultMessageKey {
+			msg = fmt.Sprint(kv[i+1])
+			continue
+		}
+		data = append(data, zap.Any(fmt.Sprint(kv[i]), kv[i+1]))
 	}
-	switch level {
-	case log.LevelDebug:
-		l.log.Debug("", data...)
-
-	case log.LevelInfo:
-		l.log.Debug("", data...)
+	l.write(zapLevel(level), msg, data...)
+	return nil
+}
 
-	case log.LevelWarn:
-		l.log.Debug("", data...)
+func (l *Logger) write(level zapcore.Level, msg string, fields ...zap.Field) {
+	ce := l.log.Check(level, msg)
+	if ce == nil {
+		return
+	}
+	if ce.Caller.Defined {
+		if c, ok := caller(); ok {
+			ce.Caller = c
+		}
+	}
+	ce.Write(fields...)
+}
 
-	case log.LevelError:
-		l.log.Debug("", data...)
+var (
+	// logPkg and zapPkg are the import paths of the packages skipped by caller.
+	logPkg = reflect.TypeOf(log.Helper{}).PkgPath()
+	zapPkg = reflect.TypeOf(Logger{}).PkgPath()
+)
 
-	case log.LevelFatal:
-		l.log.Debug("", data...)
+// caller returns the first frame outside the log package and this adapter,
+// their test files are outside as the DefaultCaller of the log package.
+func caller() (zapcore.EntryCaller, bool) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		skip := inPackage(frame.Function, logPkg) || inPackage(frame.Function, zapPkg)
+		if !skip || strings.HasSuffix(frame.File, "_test.go") {
+			return zapcore.EntryCaller{
+				Defined:  true,
+				PC:       frame.PC,
+				File:     frame.File,
+				Line:     frame.Line,
+				Function: frame.Function,
+			}, true
+		}
+		if !more {
+			return zapcore.EntryCaller{}, false
+		}
 	}
-	return nil
 }
 
+// inPackage reports whether the function, e.g. "path/to/log.(*Helper).Info", is declared in the package.
+func inPackage(function, pkg string) bool {
+	return strings.HasPrefix(function, pkg+".")
+}
+
+// Sync flushes the buffered logs of zap.
 func (l *Logger) Sync() error {
 	return l.log.Sync()
-}
\ No newline at end of file
+}
+
+func zapLevel(level log.Level) zapcore.Level {
+	switch level {
+	case log.LevelDebug:
+		return zapcore.DebugLevel
+	case log.LevelInfo:
+		return zapcore.InfoLevel
+	case log.LevelWarn:
+		return zapcore.WarnLevel
+	case log.LevelError:
+		return zapcore.ErrorLevel
+	case log.LevelFatal:
+		return zapcore.FatalLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
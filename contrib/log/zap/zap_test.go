@@ -3,6 +3,12 @@ package zap
 import (
 	"github.com/tiennampham23/kratos-cloned/log"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -13,4 +19,170 @@ func Test_ZapLogger(t *testing.T) {
 	}()
 	zLogger := log.NewHelper(logger)
 	zLogger.Debugw("log", "debug")
-}
\ No newline at end of file
+}
+
+func TestLogger_Levels(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewLogger(zap.New(core, zap.OnFatal(zapcore.WriteThenPanic)))
+	h := log.NewHelper(logger, log.WithExit(func(int) {}))
+
+	h.Debugw("key", "debug")
+	h.Infof("info %d", 1)
+	h.Warn("warn")
+	h.Errorw("msg", "error", "key", "value")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the fatal action of zap")
+			}
+		}()
+		h.Fatal("fatal")
+	}()
+
+	expected := []struct {
+		level  zapcore.Level
+		msg    string
+		fields map[string]interface{}
+	}{
+		{zapcore.DebugLevel, "", map[string]interface{}{"key": "debug"}},
+		{zapcore.InfoLevel, "info 1", map[string]interface{}{}},
+		{zapcore.WarnLevel, "warn", map[string]interface{}{}},
+		{zapcore.ErrorLevel, "error", map[string]interface{}{"key": "value"}},
+		{zapcore.FatalLevel, "fatal", map[string]interface{}{}},
+	}
+	entries := logs.AllUntimed()
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %v", len(expected), len(entries), entries)
+	}
+	for i, e := range expected {
+		got := entries[i]
+		if got.Level != e.level || got.Message != e.msg {
+			t.Errorf("entry %d: got %v %q, want %v %q", i, got.Level, got.Message, e.level, e.msg)
+		}
+		fields := got.ContextMap()
+		if len(fields) != len(e.fields) {
+			t.Errorf("entry %d: got fields %v, want %v", i, fields, e.fields)
+		}
+		for k, v := range e.fields {
+			if fields[k] != v {
+				t.Errorf("entry %d: %s = %v, want %v", i, k, fields[k], v)
+			}
+		}
+	}
+}
+
+func TestLogger_Unpaired(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewLogger(zap.New(core))
+	_ = logger.Log(log.LevelInfo, "key")
+	_ = logger.Log(log.LevelInfo)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %v", entries)
+	}
+	for _, e := range entries {
+		if e.Level != zapcore.WarnLevel || !strings.HasPrefix(e.Message, "Key values must appear in pairs") {
+			t.Errorf("unexpected entry %v %q", e.Level, e.Message)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+		deny []string
+	}{
+		{
+			name: "production",
+			want: []string{`"level":"info"`, `"msg":"info"`, `"key":"value"`, `"caller":"zap/zap_test.go:`},
+			deny: []string{"debug"},
+		},
+		{
+			name: "development",
+			opts: []Option{WithDevelopment()},
+			want: []string{"DEBUG", "debug", "INFO", "info", `{"key": "value"}`},
+		},
+		{
+			name: "level",
+			opts: []Option{WithLevel(log.LevelWarn), WithEncoding("console")},
+			want: []string{"warn"},
+			deny: []string{"info"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.name+".log")
+			logger, err := New(append(test.opts, WithOutputPaths(path))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := log.NewHelper(logger)
+			h.Debug("debug")
+			h.Infow("msg", "info", "key", "value")
+			h.Warn("warn")
+			_ = logger.Sync()
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range test.want {
+				if !strings.Contains(string(data), w) {
+					t.Errorf("expected %q in %s", w, data)
+				}
+			}
+			for _, d := range test.deny {
+				if strings.Contains(string(data), d) {
+					t.Errorf("unexpected %q in %s", d, data)
+				}
+			}
+		})
+	}
+}
+
+func TestLogger_Caller(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewLogger(zap.New(core, zap.AddCaller()))
+	log.SetLogger(logger)
+	defer log.SetLogger(log.DefaultLogger)
+
+	var lines []int
+	line := func() int {
+		_, _, l, _ := runtime.Caller(1)
+		return l
+	}
+	log.NewHelper(logger).Info("helper")
+	lines = append(lines, line()-1)
+	log.Infof("global %s", "infof")
+	lines = append(lines, line()-1)
+	log.Info("global info")
+	lines = append(lines, line()-1)
+	log.NewHelper(log.With(logger, "key", "value")).Info("with")
+	lines = append(lines, line()-1)
+	log.NewHelper(log.NewFilter(log.With(logger, "key", "value"))).Info("filter")
+	lines = append(lines, line()-1)
+
+	entries := logs.AllUntimed()
+	if len(entries) != len(lines) {
+		t.Fatalf("expected %d entries, got %d", len(lines), len(entries))
+	}
+	for i, e := range entries {
+		if filepath.Base(e.Caller.File) != "zap_test.go" || e.Caller.Line != lines[i] {
+			t.Errorf("%s: expected caller zap_test.go:%d, got %s", e.Message, lines[i], e.Caller)
+		}
+	}
+}
+
+func TestLogger_UnknownLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewLogger(zap.New(core))
+	_ = logger.Log(log.Level(42), "msg", "unknown")
+	entries := logs.AllUntimed()
+	if len(entries) != 1 || entries[0].Level != zapcore.InfoLevel || entries[0].Message != "unknown" {
+		t.Errorf("expected an info entry, got %v", entries)
+	}
+}
@@ -0,0 +1,36 @@
+package encoding
+
+import "testing"
+
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error)      { return nil, nil }
+func (codec) Unmarshal(data []byte, v interface{}) error { return nil }
+func (codec) Name() string                               { return "Mock" }
+
+type emptyCodec struct {
+	codec
+}
+
+func (emptyCodec) Name() string { return "" }
+
+func TestRegisterCodec(t *testing.T) {
+	if GetCodec("mock") != nil {
+		t.Fatal("expected no codec before registration")
+	}
+	RegisterCodec(codec{})
+	if GetCodec("mock") == nil {
+		t.Error("expected codec to be registered by lower-cased name")
+	}
+
+	for _, c := range []Codec{nil, emptyCodec{}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic registering %v", c)
+				}
+			}()
+			RegisterCodec(c)
+		}()
+	}
+}
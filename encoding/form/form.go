@@ -0,0 +1,233 @@
+package form
+
+import (
+	"fmt"
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Name is the name registered for the form codec.
+const Name = "x-www-form-urlencoded"
+
+// tagName is the struct tag naming the form fields, it is shared with the json codec.
+const tagName = "json"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec is a Codec implementation with url encoded forms, it supports url.Values,
+// string maps and structs of basic types and slices of basic types.
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	vs, err := encodeValues(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(vs.Encode()), nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	vs, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	return decodeValues(vs, v)
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+func encodeValues(v interface{}) (url.Values, error) {
+	switch m := v.(type) {
+	case url.Values:
+		return m, nil
+	case map[string][]string:
+		return url.Values(m), nil
+	case map[string]string:
+		vs := make(url.Values, len(m))
+		for k, v := range m {
+			vs.Set(k, v)
+		}
+		return vs, nil
+	case map[string]interface{}:
+		vs := make(url.Values, len(m))
+		for k, v := range m {
+			if err := addValue(vs, k, reflect.ValueOf(v)); err != nil {
+				return nil, err
+			}
+		}
+		return vs, nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form: unsupported type %T", v)
+	}
+	vs := make(url.Values)
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, omitempty := fieldName(field)
+		if name == "" {
+			continue
+		}
+		fv := rv.Field(i)
+		if omitempty && fv.IsZero() {
+			continue
+		}
+		if err := addValue(vs, name, fv); err != nil {
+			return nil, err
+		}
+	}
+	return vs, nil
+}
+
+func addValue(vs url.Values, key string, v reflect.Value) error {
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Interface {
+		return addValue(vs, key, v.Elem())
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			if err := addValue(vs, key, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Func, reflect.Chan:
+		return fmt.Errorf("form: unsupported field type %s of %s", v.Type(), key)
+	case reflect.Slice:
+		vs.Add(key, string(v.Bytes()))
+	default:
+		vs.Add(key, fmt.Sprint(v.Interface()))
+	}
+	return nil
+}
+
+func decodeValues(vs url.Values, v interface{}) error {
+	switch m := v.(type) {
+	case *url.Values:
+		*m = vs
+		return nil
+	case *map[string][]string:
+		*m = vs
+		return nil
+	case *map[string]string:
+		if *m == nil {
+			*m = make(map[string]string, len(vs))
+		}
+		for k := range vs {
+			(*m)[k] = vs.Get(k)
+		}
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("form: unsupported type %T", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, _ := fieldName(field)
+		values, ok := vs[name]
+		if name == "" || !ok {
+			continue
+		}
+		if err := setValue(rv.Field(i), values); err != nil {
+			return fmt.Errorf("form: field %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), values)
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(s.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	value := values[0]
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Slice:
+		v.SetBytes([]byte(value))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// fieldName returns the form name of the struct field and whether it is omitted when empty.
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get(tagName)
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
@@ -0,0 +1,83 @@
+package form
+
+import (
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type testUser struct {
+	Name    string   `json:"name"`
+	Age     int      `json:"age"`
+	Admin   bool     `json:"admin,omitempty"`
+	Tags    []string `json:"tags"`
+	Score   *float64 `json:"score,omitempty"`
+	Ignored string   `json:"-"`
+	Email   string
+}
+
+func TestCodec(t *testing.T) {
+	c := encoding.GetCodec(Name)
+	if c == nil {
+		t.Fatal("expected form codec to be registered")
+	}
+	score := 9.5
+	in := &testUser{Name: "kratos", Age: 3, Tags: []string{"go", "micro"}, Score: &score, Ignored: "x", Email: "a@b.c"}
+	data, err := c.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, _ := url.ParseQuery(string(data))
+	expected := url.Values{
+		"name":  {"kratos"},
+		"age":   {"3"},
+		"tags":  {"go", "micro"},
+		"score": {"9.5"},
+		"Email": {"a@b.c"},
+	}
+	if !reflect.DeepEqual(vs, expected) {
+		t.Errorf("expected %v, got %v", expected, vs)
+	}
+
+	out := new(testUser)
+	if err = c.Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+	in.Ignored = ""
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+
+	if err = c.Unmarshal([]byte("age=old"), out); err == nil {
+		t.Error("expected error decoding an invalid int")
+	}
+}
+
+func TestCodec_Maps(t *testing.T) {
+	c := encoding.GetCodec(Name)
+	data, err := c.Marshal(map[string]interface{}{"a": 1, "b": []string{"x", "y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a=1&b=x&b=y" {
+		t.Errorf("unexpected form: %s", data)
+	}
+	var m map[string]string
+	if err = c.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, map[string]string{"a": "1", "b": "x"}) {
+		t.Errorf("unexpected map: %v", m)
+	}
+	var vs url.Values
+	if err = c.Unmarshal(data, &vs); err != nil {
+		t.Fatal(err)
+	}
+	if len(vs["b"]) != 2 {
+		t.Errorf("unexpected values: %v", vs)
+	}
+	if _, err = c.Marshal(1); err == nil {
+		t.Error("expected error marshaling an int")
+	}
+}
@@ -0,0 +1,41 @@
+package json
+
+import (
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"testing"
+)
+
+type testUser struct {
+	Name string `json:"name"`
+}
+
+func TestCodec(t *testing.T) {
+	c := encoding.GetCodec(Name)
+	if c == nil {
+		t.Fatal("expected json codec to be registered")
+	}
+	data, err := c.Marshal(&testUser{Name: "kratos"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"name":"kratos"}` {
+		t.Errorf("unexpected json: %s", data)
+	}
+	var u testUser
+	if err = c.Unmarshal(data, &u); err != nil || u.Name != "kratos" {
+		t.Errorf("unexpected user %+v: %v", u, err)
+	}
+
+	data, err = c.Marshal(wrapperspb.String("kratos"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"kratos"` {
+		t.Errorf("expected protojson well-known type encoding, got %s", data)
+	}
+	m := new(wrapperspb.StringValue)
+	if err = c.Unmarshal(data, m); err != nil || m.Value != "kratos" {
+		t.Errorf("unexpected message %v: %v", m, err)
+	}
+}
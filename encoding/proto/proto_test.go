@@ -0,0 +1,25 @@
+package proto
+
+import (
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	c := encoding.GetCodec(Name)
+	if c == nil {
+		t.Fatal("expected proto codec to be registered")
+	}
+	data, err := c.Marshal(wrapperspb.String("kratos"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(wrapperspb.StringValue)
+	if err = c.Unmarshal(data, m); err != nil || m.Value != "kratos" {
+		t.Errorf("unexpected message %v: %v", m, err)
+	}
+	if _, err = c.Marshal("kratos"); err == nil {
+		t.Error("expected error marshaling a non proto message")
+	}
+}
@@ -0,0 +1,28 @@
+package xml
+
+import (
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"testing"
+)
+
+type testUser struct {
+	Name string `xml:"name"`
+}
+
+func TestCodec(t *testing.T) {
+	c := encoding.GetCodec(Name)
+	if c == nil {
+		t.Fatal("expected xml codec to be registered")
+	}
+	data, err := c.Marshal(&testUser{Name: "kratos"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "<testUser><name>kratos</name></testUser>" {
+		t.Errorf("unexpected xml: %s", data)
+	}
+	var u testUser
+	if err = c.Unmarshal(data, &u); err != nil || u.Name != "kratos" {
+		t.Errorf("unexpected user %+v: %v", u, err)
+	}
+}
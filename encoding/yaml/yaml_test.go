@@ -0,0 +1,26 @@
+package yaml
+
+import (
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"reflect"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	c := encoding.GetCodec(Name)
+	if c == nil {
+		t.Fatal("expected yaml codec to be registered")
+	}
+	in := map[string]interface{}{"server": map[string]interface{}{"addr": ":8000"}}
+	data, err := c.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]interface{})
+	if err = c.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %v, got %v", in, out)
+	}
+}
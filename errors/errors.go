@@ -0,0 +1,183 @@
+package errors
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/tiennampham23/kratos-cloned/internal/httputil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"sort"
+)
+
+const (
+	// UnknownCode is unknown code for error info.
+	UnknownCode = 500
+	// UnknownReason is unknown reason for error info.
+	UnknownReason = ""
+)
+
+// Error is a status error, it is encoded as the same shape by the HTTP and gRPC transports.
+// In XML the metadata is a list of <entry key="k">v</entry> elements sorted by key.
+type Error struct {
+	Code     int32             `json:"code" xml:"code"`
+	Reason   string            `json:"reason" xml:"reason"`
+	Message  string            `json:"message" xml:"message"`
+	Metadata map[string]string `json:"metadata" xml:"-"`
+}
+
+// xmlError is the XML shape of Error, XML does not support maps.
+type xmlError struct {
+	Code     int32        `xml:"code"`
+	Reason   string       `xml:"reason"`
+	Message  string       `xml:"message"`
+	Metadata *xmlMetadata `xml:"metadata,omitempty"`
+}
+
+type xmlMetadata struct {
+	Entries []xmlEntry `xml:"entry"`
+}
+
+type xmlEntry struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// MarshalXML encodes the error with its metadata as a list of entries.
+func (e *Error) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	x := xmlError{Code: e.Code, Reason: e.Reason, Message: e.Message}
+	keys := make([]string, 0, len(e.Metadata))
+	for k := range e.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		x.Metadata = &xmlMetadata{Entries: make([]xmlEntry, 0, len(keys))}
+	}
+	for _, k := range keys {
+		x.Metadata.Entries = append(x.Metadata.Entries, xmlEntry{Key: k, Value: e.Metadata[k]})
+	}
+	return enc.EncodeElement(x, start)
+}
+
+// UnmarshalXML decodes the error encoded by MarshalXML.
+func (e *Error) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var x xmlError
+	if err := dec.DecodeElement(&x, &start); err != nil {
+		return err
+	}
+	e.Code, e.Reason, e.Message, e.Metadata = x.Code, x.Reason, x.Message, nil
+	if x.Metadata != nil && len(x.Metadata.Entries) > 0 {
+		e.Metadata = make(map[string]string, len(x.Metadata.Entries))
+		for _, entry := range x.Metadata.Entries {
+			e.Metadata[entry.Key] = entry.Value
+		}
+	}
+	return nil
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("error: code = %d reason = %s message = %s metadata = %v", e.Code, e.Reason, e.Message, e.Metadata)
+}
+
+// Is matches each error in the chain with the target value by code and reason.
+func (e *Error) Is(err error) bool {
+	if se := new(Error); errors.As(err, &se) {
+		return se.Code == e.Code && se.Reason == e.Reason
+	}
+	return false
+}
+
+// WithMetadata with an MD formed by the mapping of key, value.
+func (e *Error) WithMetadata(md map[string]string) *Error {
+	err := Clone(e)
+	err.Metadata = md
+	return err
+}
+
+// GRPCStatus returns the Status represented by se.
+func (e *Error) GRPCStatus() *status.Status {
+	s, _ := status.New(httputil.ToGRPCCode(int(e.Code)), e.Message).
+		WithDetails(&errdetails.ErrorInfo{
+			Reason:   e.Reason,
+			Metadata: e.Metadata,
+		})
+	return s
+}
+
+// New returns an error object for the code, message.
+func New(code int, reason, message string) *Error {
+	return &Error{
+		Code:    int32(code),
+		Message: message,
+		Reason:  reason,
+	}
+}
+
+// Newf New(code fmt.Sprintf(format, a...))
+func Newf(code int, reason, format string, a ...interface{}) *Error {
+	return New(code, reason, fmt.Sprintf(format, a...))
+}
+
+// Errorf returns an error object for the code, message and error info.
+func Errorf(code int, reason, format string, a ...interface{}) error {
+	return New(code, reason, fmt.Sprintf(format, a...))
+}
+
+// Code returns the http code for an error.
+// It supports wrapped errors.
+func Code(err error) int {
+	if err == nil {
+		return 200
+	}
+	return int(FromError(err).Code)
+}
+
+// Reason returns the reason for a particular error.
+// It supports wrapped errors.
+func Reason(err error) string {
+	if err == nil {
+		return UnknownReason
+	}
+	return FromError(err).Reason
+}
+
+// Clone deep clone error to a new error.
+func Clone(err *Error) *Error {
+	if err == nil {
+		return nil
+	}
+	metadata := make(map[string]string, len(err.Metadata))
+	for k, v := range err.Metadata {
+		metadata[k] = v
+	}
+	return &Error{
+		Code:     err.Code,
+		Reason:   err.Reason,
+		Message:  err.Message,
+		Metadata: metadata,
+	}
+}
+
+// FromError try to convert an error to *Error.
+// It supports wrapped errors and gRPC status errors.
+func FromError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if se := new(Error); errors.As(err, &se) {
+		return se
+	}
+	gs, ok := status.FromError(err)
+	if !ok {
+		return New(UnknownCode, UnknownReason, err.Error())
+	}
+	ret := New(httputil.FromGRPCCode(gs.Code()), UnknownReason, gs.Message())
+	for _, detail := range gs.Details() {
+		if d, ok := detail.(*errdetails.ErrorInfo); ok {
+			ret.Reason = d.Reason
+			return ret.WithMetadata(d.Metadata)
+		}
+	}
+	return ret
+}
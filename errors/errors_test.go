@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	base := New(http.StatusBadRequest, "USER_NAME_EMPTY", "user name is empty")
+	err := base.WithMetadata(map[string]string{"field": "name"})
+	if !errors.Is(err, base) {
+		t.Error("expected errors to match by code and reason")
+	}
+	if errors.Is(err, New(http.StatusBadRequest, "OTHER", "")) {
+		t.Error("expected errors with another reason not to match")
+	}
+	wrapped := fmt.Errorf("wrap: %w", err)
+	if !errors.Is(wrapped, base) || !IsBadRequest(wrapped) {
+		t.Error("expected wrapped error to match")
+	}
+	if Code(wrapped) != http.StatusBadRequest || Reason(wrapped) != "USER_NAME_EMPTY" {
+		t.Errorf("unexpected code %d and reason %s", Code(wrapped), Reason(wrapped))
+	}
+	if Code(nil) != http.StatusOK || Reason(nil) != UnknownReason {
+		t.Error("unexpected code and reason of nil error")
+	}
+	if base.Metadata != nil {
+		t.Error("WithMetadata should not modify the original error")
+	}
+
+	se := FromError(wrapped)
+	if se.Metadata["field"] != "name" || se.Message != "user name is empty" {
+		t.Errorf("unexpected error: %v", se)
+	}
+	if se = FromError(errors.New("plain")); se.Code != UnknownCode || se.Message != "plain" {
+		t.Errorf("unexpected error: %v", se)
+	}
+	if FromError(nil) != nil {
+		t.Error("expected nil error")
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	err := NotFound("USER_NOT_FOUND", "user not found").WithMetadata(map[string]string{"id": "1"})
+	gs, ok := status.FromError(err)
+	if !ok {
+		t.Fatal("expected grpc status")
+	}
+	if gs.Code() != codes.NotFound || gs.Message() != "user not found" {
+		t.Errorf("unexpected status: %v", gs)
+	}
+
+	se := FromError(gs.Err())
+	if se.Code != http.StatusNotFound || se.Reason != "USER_NOT_FOUND" || se.Metadata["id"] != "1" {
+		t.Errorf("unexpected error: %v", se)
+	}
+	if !IsNotFound(gs.Err()) {
+		t.Error("expected status error to be not found")
+	}
+
+	se = FromError(status.Error(codes.Unavailable, "unavailable"))
+	if !IsServiceUnavailable(se) || se.Reason != UnknownReason {
+		t.Errorf("unexpected error: %v", se)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	testCases := []struct {
+		err *Error
+		is  func(error) bool
+	}{
+		{BadRequest("", ""), IsBadRequest},
+		{Unauthorized("", ""), IsUnauthorized},
+		{Forbidden("", ""), IsForbidden},
+		{NotFound("", ""), IsNotFound},
+		{Conflict("", ""), IsConflict},
+		{InternalServer("", ""), IsInternalServer},
+		{ServiceUnavailable("", ""), IsServiceUnavailable},
+		{GatewayTimeout("", ""), IsGatewayTimeout},
+		{ClientClosed("", ""), IsClientClosed},
+	}
+	for _, tc := range testCases {
+		if !tc.is(tc.err) {
+			t.Errorf("expected %d to match", tc.err.Code)
+		}
+	}
+	if IsNotFound(BadRequest("", "")) {
+		t.Error("bad request should not be not found")
+	}
+}
+
+func TestError_XML(t *testing.T) {
+	err := NotFound("USER_NOT_FOUND", "user not found").WithMetadata(map[string]string{"name": "kratos", "id": "1"})
+	data, e := xml.Marshal(err)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expected := `<Error><code>404</code><reason>USER_NOT_FOUND</reason><message>user not found</message>` +
+		`<metadata><entry key="id">1</entry><entry key="name">kratos</entry></metadata></Error>`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+	se := new(Error)
+	if e = xml.Unmarshal(data, se); e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(se, err) {
+		t.Errorf("expected %v, got %v", err, se)
+	}
+}
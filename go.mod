@@ -3,7 +3,13 @@ module github.com/tiennampham23/kratos-cloned
 go 1.16
 
 require (
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/google/uuid v1.3.0
 	github.com/gorilla/mux v1.8.0
 	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
+	golang.org/x/sys v0.13.0 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
+	google.golang.org/grpc v1.44.0
+	google.golang.org/protobuf v1.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
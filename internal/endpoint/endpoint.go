@@ -0,0 +1,38 @@
+package endpoint
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// NewEndpoint returns a registry endpoint like http://127.0.0.1:8000?isSecure=false.
+func NewEndpoint(scheme, host string, isSecure bool) *url.URL {
+	return &url.URL{
+		Scheme:   scheme,
+		Host:     host,
+		RawQuery: "isSecure=" + strconv.FormatBool(isSecure),
+	}
+}
+
+// IsSecure reports whether the endpoint is marked with isSecure=true.
+func IsSecure(u *url.URL) bool {
+	ok, err := strconv.ParseBool(u.Query().Get("isSecure"))
+	if err != nil {
+		return false
+	}
+	return ok
+}
+
+// ParseEndpoint returns the host of the first endpoint matching the scheme and isSecure.
+func ParseEndpoint(endpoints []string, scheme string, isSecure bool) (string, error) {
+	for _, e := range endpoints {
+		u, err := url.Parse(e)
+		if err != nil {
+			return "", err
+		}
+		if u.Scheme == scheme && IsSecure(u) == isSecure {
+			return u.Host, nil
+		}
+	}
+	return "", nil
+}
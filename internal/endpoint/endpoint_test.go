@@ -0,0 +1,50 @@
+package endpoint
+
+import "testing"
+
+func TestNewEndpoint(t *testing.T) {
+	u := NewEndpoint("https", "127.0.0.1:8000", true)
+	if u.String() != "https://127.0.0.1:8000?isSecure=true" {
+		t.Errorf("unexpected endpoint: %s", u)
+	}
+	if !IsSecure(u) {
+		t.Error("expected secure endpoint")
+	}
+	u = NewEndpoint("http", "127.0.0.1:8000", false)
+	if u.String() != "http://127.0.0.1:8000?isSecure=false" {
+		t.Errorf("unexpected endpoint: %s", u)
+	}
+	if IsSecure(u) {
+		t.Error("expected insecure endpoint")
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	endpoints := []string{
+		"http://127.0.0.1:8000?isSecure=false",
+		"grpc://127.0.0.1:9000?isSecure=false",
+		"grpc://127.0.0.1:9443?isSecure=true",
+	}
+	testCases := []struct {
+		scheme   string
+		isSecure bool
+		expected string
+	}{
+		{"http", false, "127.0.0.1:8000"},
+		{"grpc", false, "127.0.0.1:9000"},
+		{"grpc", true, "127.0.0.1:9443"},
+		{"http", true, ""},
+	}
+	for _, tc := range testCases {
+		host, err := ParseEndpoint(endpoints, tc.scheme, tc.isSecure)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host != tc.expected {
+			t.Errorf("%s %v: expected %q, got %q", tc.scheme, tc.isSecure, tc.expected, host)
+		}
+	}
+	if _, err := ParseEndpoint([]string{"%zz"}, "grpc", false); err == nil {
+		t.Error("expected error for invalid endpoint")
+	}
+}
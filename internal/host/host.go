@@ -0,0 +1,82 @@
+package host
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// Port returns the real port of the listener.
+func Port(lis net.Listener) (int, bool) {
+	if addr, ok := lis.Addr().(*net.TCPAddr); ok {
+		return addr.Port, true
+	}
+	return 0, false
+}
+
+// Extract returns a routable host and port, the port is taken from lis when it is set,
+// and an unspecified host like "" or "0.0.0.0" is replaced with the IP of an active interface.
+func Extract(hostPort string, lis net.Listener) (string, error) {
+	addr, port, err := net.SplitHostPort(hostPort)
+	if err != nil && lis == nil {
+		return "", err
+	}
+	if lis != nil {
+		p, ok := Port(lis)
+		if !ok {
+			return "", fmt.Errorf("failed to extract port: %v", lis.Addr())
+		}
+		port = strconv.Itoa(p)
+	}
+	if ip := net.ParseIP(addr); len(addr) > 0 && (ip == nil || !ip.IsUnspecified()) {
+		return net.JoinHostPort(addr, port), nil
+	}
+	ip, err := hostIP()
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(ip.String(), port), nil
+}
+
+// hostIP returns the first global unicast IP of the active interfaces, preferring IPv4,
+// it falls back to the loopback address when the host has no routable IP.
+func hostIP() (net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var found net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, rawAddr := range addrs {
+			var ip net.IP
+			switch addr := rawAddr.(type) {
+			case *net.IPAddr:
+				ip = addr.IP
+			case *net.IPNet:
+				ip = addr.IP
+			default:
+				continue
+			}
+			if !ip.IsGlobalUnicast() {
+				continue
+			}
+			if ip.To4() != nil {
+				return ip, nil
+			}
+			if found == nil {
+				found = ip
+			}
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+	return net.IPv4(127, 0, 0, 1), nil
+}
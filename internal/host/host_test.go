@@ -0,0 +1,60 @@
+package host
+
+import (
+	"net"
+	"strconv"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	port, ok := Port(lis)
+	if !ok {
+		t.Fatal("expected tcp listener port")
+	}
+
+	testCases := []struct {
+		hostPort string
+		lis      net.Listener
+		expected string
+	}{
+		{"127.0.0.1:8000", nil, "127.0.0.1:8000"},
+		{"10.0.0.1:8000", lis, net.JoinHostPort("10.0.0.1", strconv.Itoa(port))},
+		{"[::1]:8000", nil, "[::1]:8000"},
+		{"localhost:8000", nil, "localhost:8000"},
+	}
+	for _, tc := range testCases {
+		got, err := Extract(tc.hostPort, tc.lis)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.hostPort, tc.expected, got)
+		}
+	}
+
+	for _, hostPort := range []string{":0", "0.0.0.0:0", "[::]:0"} {
+		got, err := Extract(hostPort, lis)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, p, err := net.SplitHostPort(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ip := net.ParseIP(h); ip == nil || ip.IsUnspecified() {
+			t.Errorf("%s: expected a host ip, got %s", hostPort, h)
+		}
+		if p != strconv.Itoa(port) {
+			t.Errorf("%s: expected port %d, got %s", hostPort, port, p)
+		}
+	}
+
+	if _, err := Extract("invalid", nil); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
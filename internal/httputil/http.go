@@ -0,0 +1,32 @@
+package httputil
+
+import (
+	"strings"
+)
+
+const (
+	baseContentType = "application"
+)
+
+// ContentType returns the content-type with base prefix.
+func ContentType(subtype string) string {
+	return strings.Join([]string{baseContentType, subtype}, "/")
+}
+
+// ContentSubtype returns the content-subtype for the given content-type, e.g. "json"
+// for "application/json; charset=utf-8" or "application/problem+json".
+// The content-subtype is returned as lower-cased, "" is returned for an invalid content-type.
+func ContentSubtype(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	left := strings.Index(contentType, "/")
+	if left == -1 {
+		return ""
+	}
+	contentType = strings.TrimSpace(contentType[left+1:])
+	if i := strings.LastIndex(contentType, "+"); i >= 0 {
+		contentType = contentType[i+1:]
+	}
+	return strings.ToLower(contentType)
+}
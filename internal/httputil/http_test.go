@@ -0,0 +1,26 @@
+package httputil
+
+import "testing"
+
+func TestContentSubtype(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		expected    string
+	}{
+		{"application/json", "json"},
+		{"application/json; charset=utf-8", "json"},
+		{"application/problem+json", "json"},
+		{"application/X-WWW-FORM-URLENCODED", "x-www-form-urlencoded"},
+		{"text/xml;charset=utf-8", "xml"},
+		{"json", ""},
+		{"", ""},
+	}
+	for _, tc := range testCases {
+		if got := ContentSubtype(tc.contentType); got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.contentType, tc.expected, got)
+		}
+	}
+	if ContentType("json") != "application/json" {
+		t.Errorf("unexpected content type: %s", ContentType("json"))
+	}
+}
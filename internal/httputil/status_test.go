@@ -0,0 +1,41 @@
+package httputil
+
+import (
+	"google.golang.org/grpc/codes"
+	"net/http"
+	"testing"
+)
+
+func TestGRPCCode(t *testing.T) {
+	testCases := []struct {
+		code  int
+		gcode codes.Code
+	}{
+		{http.StatusOK, codes.OK},
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusUnauthorized, codes.Unauthenticated},
+		{http.StatusForbidden, codes.PermissionDenied},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusConflict, codes.Aborted},
+		{http.StatusTooManyRequests, codes.ResourceExhausted},
+		{ClientClosed, codes.Canceled},
+		{http.StatusInternalServerError, codes.Internal},
+		{http.StatusNotImplemented, codes.Unimplemented},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+		{http.StatusGatewayTimeout, codes.DeadlineExceeded},
+	}
+	for _, tc := range testCases {
+		if got := ToGRPCCode(tc.code); got != tc.gcode {
+			t.Errorf("ToGRPCCode(%d): expected %s, got %s", tc.code, tc.gcode, got)
+		}
+		if got := FromGRPCCode(tc.gcode); got != tc.code {
+			t.Errorf("FromGRPCCode(%s): expected %d, got %d", tc.gcode, tc.code, got)
+		}
+	}
+	if got := ToGRPCCode(http.StatusTeapot); got != codes.Unknown {
+		t.Errorf("expected unknown code, got %s", got)
+	}
+	if got := FromGRPCCode(codes.DataLoss); got != http.StatusInternalServerError {
+		t.Errorf("expected internal server error, got %d", got)
+	}
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// EncoderOption is option of the structured loggers.
+type EncoderOption func(*encoderOptions)
+
+type encoderOptions struct {
+	levelKey string
+	msgKey   string
+	timeKey  string
+	now      func() time.Time
+}
+
+// LevelKey with the key of the level field, "level" by default.
+func LevelKey(key string) EncoderOption {
+	return func(o *encoderOptions) {
+		o.levelKey = key
+	}
+}
+
+// MessageKey with the key the DefaultMessageKey field is renamed to, "msg" by default.
+func MessageKey(key string) EncoderOption {
+	return func(o *encoderOptions) {
+		o.msgKey = key
+	}
+}
+
+// TimeKey with the key of the RFC3339Nano timestamp field, "ts" by default,
+// an empty key disables the timestamp.
+func TimeKey(key string) EncoderOption {
+	return func(o *encoderOptions) {
+		o.timeKey = key
+	}
+}
+
+// field is a key value of a log entry.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// encoder writes the log entries of the structured loggers, each entry is
+// a single write of a buffer from the pool.
+type encoder struct {
+	opts encoderOptions
+	pool *sync.Pool
+	lock sync.Mutex
+	w    io.Writer
+	// encode appends the fields as a line to the buffer.
+	encode func(*bytes.Buffer, []field)
+}
+
+func newEncoder(w io.Writer, encode func(*bytes.Buffer, []field), opts ...EncoderOption) *encoder {
+	o := encoderOptions{
+		levelKey: "level",
+		msgKey:   DefaultMessageKey,
+		timeKey:  "ts",
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &encoder{
+		opts: o,
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return new(bytes.Buffer)
+			},
+		},
+		w:      w,
+		encode: encode,
+	}
+}
+
+func (e *encoder) Log(level Level, kvs ...interface{}) error {
+	if len(kvs) == 0 {
+		return nil
+	}
+	if (len(kvs) & 1) == 1 {
+		kvs = append(kvs, "KEY_VALUES UNPAIRED")
+	}
+	buf := e.pool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		e.pool.Put(buf)
+	}()
+	e.encode(buf, e.fields(level, kvs))
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
+
+// fields orders the fields of an entry: the timestamp, the level, the message
+// and then the kvs in the order they are given.
+func (e *encoder) fields(level Level, kvs []interface{}) []field {
+	fields := make([]field, 0, len(kvs)/2+3)
+	if e.opts.timeKey != "" {
+		fields = append(fields, field{e.opts.timeKey, e.opts.now()})
+	}
+	fields = append(fields, field{e.opts.levelKey, level.String()})
+	msg := -1
+	for i := 0; i < len(kvs); i += 2 {
+		if kvs[i] == DefaultMessageKey {
+			msg = i
+			fields = append(fields, field{e.opts.msgKey, kvs[i+1]})
+			break
+		}
+	}
+	for i := 0; i < len(kvs); i += 2 {
+		if i == msg {
+			continue
+		}
+		fields = append(fields, field{e.key(kvs[i]), kvs[i+1]})
+	}
+	return fields
+}
+
+// key returns the key of a logged field, the keys colliding with the timestamp,
+// level or message keys are prefixed with "fields." to keep the keys unique.
+func (e *encoder) key(k interface{}) string {
+	key := fmt.Sprint(k)
+	if (key == e.opts.timeKey && key != "") || key == e.opts.levelKey || key == e.opts.msgKey {
+		return "fields." + key
+	}
+	return key
+}
+
+// safeString returns the text of an error or a fmt.Stringer like fmt does, the
+// method of a nil pointer receiver printing "<nil>" instead of panicking.
+func safeString(v interface{}, method string, f func() string) (s string) {
+	defer func() {
+		if p := recover(); p != nil {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				s = "<nil>"
+				return
+			}
+			s = fmt.Sprintf("%%!v(PANIC=%s method: %v)", method, p)
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,121 @@
+package log
+
+// fuzzyStr replaces the values of the filtered keys and values.
+const fuzzyStr = "***"
+
+// FilterOption is filter option.
+type FilterOption func(*Filter)
+
+// FilterLevel with filter level, the logs below the level are dropped.
+func FilterLevel(level Level) FilterOption {
+	return func(opts *Filter) {
+		opts.level = level
+	}
+}
+
+// FilterKey with filter key, the values of the keys are replaced with "***".
+func FilterKey(key ...string) FilterOption {
+	return func(o *Filter) {
+		for _, v := range key {
+			o.key[v] = struct{}{}
+		}
+	}
+}
+
+// FilterValue with filter value, the matched values are replaced with "***".
+func FilterValue(value ...string) FilterOption {
+	return func(o *Filter) {
+		for _, v := range value {
+			o.value[v] = struct{}{}
+		}
+	}
+}
+
+// FilterFunc with filter func, the logs for which it returns true are dropped.
+func FilterFunc(f func(level Level, kvs ...interface{}) bool) FilterOption {
+	return func(o *Filter) {
+		o.filter = f
+	}
+}
+
+// Filter is a logger filter.
+type Filter struct {
+	logger Logger
+	// next is the logger written to, the wrapped logger with its prefix redacted.
+	next   Logger
+	level  Level
+	key    map[string]struct{}
+	value  map[string]struct{}
+	filter func(level Level, kvs ...interface{}) bool
+}
+
+// NewFilter new a logger filter.
+func NewFilter(l Logger, opts ...FilterOption) *Filter {
+	options := Filter{
+		logger: l,
+		level:  LevelDebug,
+		key:    make(map[string]struct{}),
+		value:  make(map[string]struct{}),
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	options.next = l
+	if c, ok := l.(*logger); ok && len(c.prefix) > 0 && options.redacts() {
+		prefix := options.redact(c.prefix)
+		options.next = &logger{
+			logger:    c.logger,
+			prefix:    prefix,
+			hasValuer: containsValuer(prefix),
+			ctx:       c.ctx,
+		}
+	}
+	return &options
+}
+
+// Log Print log by level and keyvals.
+func (f *Filter) Log(level Level, kvs ...interface{}) error {
+	if level < f.level {
+		return nil
+	}
+	// the filter func gets the prefix of the wrapped logger followed by kvs,
+	// the Valuer values of the prefix are not evaluated yet.
+	if f.filter != nil {
+		all := kvs
+		if l, ok := f.logger.(*logger); ok && len(l.prefix) > 0 {
+			all = make([]interface{}, 0, len(l.prefix)+len(kvs))
+			all = append(append(all, l.prefix...), kvs...)
+		}
+		if f.filter(level, all...) {
+			return nil
+		}
+	}
+	if f.redacts() {
+		kvs = f.redact(kvs)
+	}
+	return f.next.Log(level, kvs...)
+}
+
+func (f *Filter) redacts() bool {
+	return len(f.key) > 0 || len(f.value) > 0
+}
+
+// redact replaces the values of the filtered keys and values, it copies kvs
+// to leave the caller's untouched.
+func (f *Filter) redact(kvs []interface{}) []interface{} {
+	kvs = append(make([]interface{}, 0, len(kvs)), kvs...)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if k, ok := kvs[i].(string); ok {
+			if _, ok := f.key[k]; ok {
+				kvs[i+1] = fuzzyStr
+				continue
+			}
+		}
+		if v, ok := kvs[i+1].(string); ok {
+			if _, ok := f.value[v]; ok {
+				kvs[i+1] = fuzzyStr
+			}
+		}
+	}
+	return kvs
+}
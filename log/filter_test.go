@@ -0,0 +1,97 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewFilter(NewStdLogger(buffer),
+		FilterLevel(LevelInfo),
+		FilterKey("password"),
+		FilterValue("secret-token"),
+		FilterFunc(func(level Level, kvs ...interface{}) bool {
+			for i := 0; i+1 < len(kvs); i += 2 {
+				if kvs[i] == "drop" {
+					return true
+				}
+			}
+			return false
+		}),
+	)
+	h := NewHelper(logger)
+
+	h.Debug("debug noise")
+	h.Infow("user", "kratos", "password", "123456")
+	kvs := []interface{}{"token", "secret-token", "id", []byte("1")}
+	h.Warnw(kvs...)
+	h.Errorw("drop", true, "msg", "dropped")
+	h.Errorw("unpaired")
+	h.Error("error")
+
+	expected := []string{
+		"INFO user=kratos password=***",
+		"WARN token=*** id=[49]",
+		"ERROR unpaired=KEY_VALUES UNPAIRED",
+		"ERROR msg=error",
+		"",
+	}
+	if got := buffer.String(); got != strings.Join(expected, "\n") {
+		t.Errorf("Expected: %v, got: %v", strings.Join(expected, "\n"), got)
+	}
+	if kvs[1] != "secret-token" {
+		t.Errorf("the filter modified the caller's kvs: %v", kvs)
+	}
+}
+
+func TestFilter_Prefix(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewFilter(With(NewStdLogger(buffer), "module", "internal"),
+		FilterFunc(func(level Level, kvs ...interface{}) bool {
+			return len(kvs) > 1 && kvs[0] == "module" && kvs[1] == "internal" && level < LevelError
+		}),
+	)
+	_ = logger.Log(LevelInfo, "msg", "dropped")
+	_ = logger.Log(LevelError, "msg", "kept")
+	if got, want := buffer.String(), "ERROR module=internal msg=kept\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+}
+
+func TestFilter_RedactPrefix(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	prefixed := With(NewStdLogger(buffer), "password", "123456", "token", "secret-token", "user", "kratos")
+	logger := NewFilter(prefixed, FilterKey("password"), FilterValue("secret-token"))
+	_ = logger.Log(LevelInfo, "msg", "login")
+	if got, want := buffer.String(), "INFO password=*** token=*** user=kratos msg=login\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+	// the wrapped logger keeps its prefix
+	buffer.Reset()
+	_ = prefixed.Log(LevelInfo, "msg", "raw")
+	if got, want := buffer.String(), "INFO password=123456 token=secret-token user=kratos msg=raw\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+}
+
+func TestFilter_PrefixAndKvs(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	calls := 0
+	logger := NewFilter(With(NewStdLogger(buffer), "module", "internal"),
+		FilterFunc(func(level Level, kvs ...interface{}) bool {
+			calls++
+			// the prefix and kvs are matched together
+			return len(kvs) == 4 && kvs[1] == "internal" && kvs[3] == "noise"
+		}),
+	)
+	_ = logger.Log(LevelInfo, "msg", "noise")
+	_ = logger.Log(LevelInfo, "msg", "kept")
+	if got, want := buffer.String(), "INFO module=internal msg=kept\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+	if calls != 2 {
+		t.Errorf("expected the filter func to be called once per log, called %d times", calls)
+	}
+}
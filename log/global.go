@@ -1,15 +1,19 @@
 package log
 
-import "sync"
+import (
+	"os"
+	"sync"
+)
 
 // globalLogger is designed as a global logger in current process.
-var global = &loggerAppliance{}
+var global = &loggerAppliance{exit: os.Exit}
 
 // loggerAppliance is the proxy of `Logger` to make logger change will affect to all sub-logger
 type loggerAppliance struct {
 	lock sync.Mutex
 	Logger
 	helper *Helper
+	exit   func(code int)
 }
 
 func init() {
@@ -22,14 +26,101 @@ func SetLogger(logger Logger) {
 	global.SetLogger(logger)
 }
 
+// SetExit sets the exit hook called by the global Fatal functions, os.Exit by default.
+func SetExit(fn func(code int)) {
+	global.SetExit(fn)
+}
+
 func (a *loggerAppliance) SetLogger(in Logger) {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 	a.Logger = in
-	a.helper = NewHelper(a.Logger)
+	a.helper = NewHelper(a.Logger, WithExit(a.exit))
+}
+
+func (a *loggerAppliance) SetExit(fn func(code int)) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.exit = fn
+	a.helper = NewHelper(a.Logger, WithExit(a.exit))
+}
+
+// Log Print log by level and keyvals.
+func Log(level Level, kvs ...interface{}) {
+	global.helper.Log(level, kvs...)
+}
+
+// Debug logs a message at debug level.
+func Debug(a ...interface{}) {
+	global.helper.Debug(a...)
+}
+
+// Debugf logs a message at debug level.
+func Debugf(format string, a ...interface{}) {
+	global.helper.Debugf(format, a...)
+}
+
+// Debugw logs a message at debug level.
+func Debugw(kvs ...interface{}) {
+	global.helper.Debugw(kvs...)
+}
+
+// Info logs a message at info level.
+func Info(a ...interface{}) {
+	global.helper.Info(a...)
+}
+
+// Infof logs a message at info level.
+func Infof(format string, a ...interface{}) {
+	global.helper.Infof(format, a...)
+}
+
+// Infow logs a message at info level.
+func Infow(kvs ...interface{}) {
+	global.helper.Infow(kvs...)
+}
+
+// Warn logs a message at warn level.
+func Warn(a ...interface{}) {
+	global.helper.Warn(a...)
+}
+
+// Warnf logs a message at warn level.
+func Warnf(format string, a ...interface{}) {
+	global.helper.Warnf(format, a...)
+}
+
+// Warnw logs a message at warn level.
+func Warnw(kvs ...interface{}) {
+	global.helper.Warnw(kvs...)
+}
+
+// Error logs a message at error level.
+func Error(a ...interface{}) {
+	global.helper.Error(a...)
 }
 
 // Errorf logs a message at error level.
-func Errorf(format string, kv ...interface{}) {
-	global.helper.Errorf(format, kv...)
+func Errorf(format string, a ...interface{}) {
+	global.helper.Errorf(format, a...)
+}
+
+// Errorw logs a message at error level.
+func Errorw(kvs ...interface{}) {
+	global.helper.Errorw(kvs...)
+}
+
+// Fatal logs a message at fatal level, then calls the exit hook with code 1.
+func Fatal(a ...interface{}) {
+	global.helper.Fatal(a...)
+}
+
+// Fatalf logs a message at fatal level, then calls the exit hook with code 1.
+func Fatalf(format string, a ...interface{}) {
+	global.helper.Fatalf(format, a...)
+}
+
+// Fatalw logs a message at fatal level, then calls the exit hook with code 1.
+func Fatalw(kvs ...interface{}) {
+	global.helper.Fatalw(kvs...)
 }
@@ -3,36 +3,87 @@ package log
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 )
 
 func Test_GlobalLog(t *testing.T) {
 	buffer := &bytes.Buffer{}
+	var exitCodes []int
+	SetExit(func(code int) { exitCodes = append(exitCodes, code) })
 	SetLogger(NewStdLogger(buffer))
+	defer func() {
+		SetExit(os.Exit)
+		SetLogger(DefaultLogger)
+	}()
 
-	testCases := []struct{
+	testCases := []struct {
 		level   Level
 		content []interface{}
 	}{
 		{
-			level: LevelError,
+			level:   LevelDebug,
+			content: []interface{}{"test debug"},
+		},
+		{
+			level:   LevelInfo,
+			content: []interface{}{"test info"},
+		},
+		{
+			level:   LevelInfo,
+			content: []interface{}{"test %s", "info"},
+		},
+		{
+			level:   LevelWarn,
+			content: []interface{}{"test warn"},
+		},
+		{
+			level:   LevelError,
 			content: []interface{}{"test error"},
 		},
+		{
+			level:   LevelError,
+			content: []interface{}{"test %s", "error"},
+		},
+		{
+			level:   LevelFatal,
+			content: []interface{}{"test %s", "fatal"},
+		},
 	}
 
 	expected := make([]string, 0)
 	for _, tc := range testCases {
 		msg := fmt.Sprintf(tc.content[0].(string), tc.content[1:]...)
 		switch tc.level {
+		case LevelDebug:
+			Debugf(tc.content[0].(string), tc.content[1:]...)
+		case LevelInfo:
+			Infof(tc.content[0].(string), tc.content[1:]...)
+		case LevelWarn:
+			Warnf(tc.content[0].(string), tc.content[1:]...)
 		case LevelError:
 			Errorf(tc.content[0].(string), tc.content[1:]...)
-			expected = append(expected, fmt.Sprintf("%v msg=%s", "ERROR", msg))
+		case LevelFatal:
+			Fatalf(tc.content[0].(string), tc.content[1:]...)
 		}
+		expected = append(expected, fmt.Sprintf("%v msg=%s", tc.level, msg))
 	}
+	Info("test", " ", "plain")
+	expected = append(expected, "INFO msg=test plain")
+	Warnw("key", "value")
+	expected = append(expected, "WARN key=value")
+	Fatal("test fatal")
+	expected = append(expected, "FATAL msg=test fatal")
+	Log(LevelDebug, "key", "value")
+	expected = append(expected, "DEBUG key=value")
+
 	expected = append(expected, "")
 	t.Logf("Content: %v", buffer.String())
 	if buffer.String() != strings.Join(expected, "\n") {
 		t.Errorf("Expected: %v, got: %v", strings.Join(expected, "\n"), buffer.String())
 	}
-}
\ No newline at end of file
+	if len(exitCodes) != 2 || exitCodes[0] != 1 || exitCodes[1] != 1 {
+		t.Errorf("expected two exits with code 1, got %v", exitCodes)
+	}
+}
@@ -1,20 +1,43 @@
 package log
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+)
 
+// DefaultMessageKey is the key of the message logged by the Helper.
 var DefaultMessageKey = "msg"
 
+// Helper is a logger helper.
 type Helper struct {
 	logger Logger
 	msgKey string
+	exit   func(code int)
 }
 
+// Option is Helper option.
 type Option func(*Helper)
 
+// WithMessageKey with message key.
+func WithMessageKey(k string) Option {
+	return func(opts *Helper) {
+		opts.msgKey = k
+	}
+}
+
+// WithExit with the exit hook called by the Fatal logs, os.Exit by default.
+func WithExit(fn func(code int)) Option {
+	return func(opts *Helper) {
+		opts.exit = fn
+	}
+}
+
+// NewHelper new a logger helper.
 func NewHelper(logger Logger, opts ...Option) *Helper {
 	options := &Helper{
 		msgKey: DefaultMessageKey,
 		logger: logger,
+		exit:   os.Exit,
 	}
 	for _, o := range opts {
 		o(options)
@@ -22,19 +45,85 @@ func NewHelper(logger Logger, opts ...Option) *Helper {
 	return options
 }
 
+// Log Print log by level and keyvals.
 func (h *Helper) Log(level Level, kvs ...interface{}) {
 	_ = h.logger.Log(level, kvs...)
 }
 
-func (h *Helper) Errorf(format string, kv ...interface{}) {
-	h.Log(LevelError, h.msgKey, fmt.Sprintf(format, kv...))
+// Debug logs a message at debug level.
+func (h *Helper) Debug(a ...interface{}) {
+	h.Log(LevelDebug, h.msgKey, fmt.Sprint(a...))
+}
+
+// Debugf logs a message at debug level.
+func (h *Helper) Debugf(format string, a ...interface{}) {
+	h.Log(LevelDebug, h.msgKey, fmt.Sprintf(format, a...))
+}
+
+// Debugw logs a message at debug level.
+func (h *Helper) Debugw(kvs ...interface{}) {
+	h.Log(LevelDebug, kvs...)
+}
+
+// Info logs a message at info level.
+func (h *Helper) Info(a ...interface{}) {
+	h.Log(LevelInfo, h.msgKey, fmt.Sprint(a...))
+}
+
+// Infof logs a message at info level.
+func (h *Helper) Infof(format string, a ...interface{}) {
+	h.Log(LevelInfo, h.msgKey, fmt.Sprintf(format, a...))
+}
+
+// Infow logs a message at info level.
+func (h *Helper) Infow(kvs ...interface{}) {
+	h.Log(LevelInfo, kvs...)
 }
 
-func (h *Helper) Debug(kv ...interface{}) {
-	h.Log(LevelDebug, h.msgKey, fmt.Sprint(kv...))
+// Warn logs a message at warn level.
+func (h *Helper) Warn(a ...interface{}) {
+	h.Log(LevelWarn, h.msgKey, fmt.Sprint(a...))
 }
 
-// Debugw logs a message at debugg level
-func (h *Helper) Debugw(kv ...interface{}) {
-	h.Log(LevelDebug, kv...)
-}
\ No newline at end of file
+// Warnf logs a message at warn level.
+func (h *Helper) Warnf(format string, a ...interface{}) {
+	h.Log(LevelWarn, h.msgKey, fmt.Sprintf(format, a...))
+}
+
+// Warnw logs a message at warn level.
+func (h *Helper) Warnw(kvs ...interface{}) {
+	h.Log(LevelWarn, kvs...)
+}
+
+// Error logs a message at error level.
+func (h *Helper) Error(a ...interface{}) {
+	h.Log(LevelError, h.msgKey, fmt.Sprint(a...))
+}
+
+// Errorf logs a message at error level.
+func (h *Helper) Errorf(format string, a ...interface{}) {
+	h.Log(LevelError, h.msgKey, fmt.Sprintf(format, a...))
+}
+
+// Errorw logs a message at error level.
+func (h *Helper) Errorw(kvs ...interface{}) {
+	h.Log(LevelError, kvs...)
+}
+
+// Fatal logs a message at fatal level, then calls the exit hook with code 1.
+func (h *Helper) Fatal(a ...interface{}) {
+	h.Log(LevelFatal, h.msgKey, fmt.Sprint(a...))
+	h.exit(1)
+}
+
+// Fatalf logs a message at fatal level, then calls the exit hook with code 1.
+func (h *Helper) Fatalf(format string, a ...interface{}) {
+	h.Log(LevelFatal, h.msgKey, fmt.Sprintf(format, a...))
+	h.exit(1)
+}
+
+// Fatalw logs a message at fatal level, then calls the exit hook with code 1.
+func (h *Helper) Fatalw(kvs ...interface{}) {
+	h.Log(LevelFatal, kvs...)
+	h.exit(1)
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHelper(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	exitCode := -1
+	h := NewHelper(NewStdLogger(buffer), WithMessageKey("message"), WithExit(func(code int) { exitCode = code }))
+
+	h.Debug("debug", 1)
+	h.Debugf("debug %d", 2)
+	h.Debugw("key", "debug")
+	h.Info("info")
+	h.Infof("info %d", 2)
+	h.Infow("key", "info")
+	h.Warn("warn")
+	h.Warnf("warn %d", 2)
+	h.Warnw("key", "warn")
+	h.Error("error")
+	h.Errorf("error %d", 2)
+	h.Errorw("key", "error")
+	if exitCode != -1 {
+		t.Fatalf("unexpected exit with code %d", exitCode)
+	}
+	h.Fatalw("key", "fatal")
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+
+	expected := []string{
+		"DEBUG message=debug1",
+		"DEBUG message=debug 2",
+		"DEBUG key=debug",
+		"INFO message=info",
+		"INFO message=info 2",
+		"INFO key=info",
+		"WARN message=warn",
+		"WARN message=warn 2",
+		"WARN key=warn",
+		"ERROR message=error",
+		"ERROR message=error 2",
+		"ERROR key=error",
+		"FATAL key=fatal",
+		"",
+	}
+	if got := buffer.String(); got != strings.Join(expected, "\n") {
+		t.Errorf("Expected: %v, got: %v", strings.Join(expected, "\n"), got)
+	}
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NewJSONLogger new a logger writing the entries as JSON objects, one per line,
+// with the fields in the order they are logged.
+//
+//	{"ts":"2006-01-02T15:04:05.999999999Z07:00","level":"INFO","msg":"hello","user":"kratos"}
+func NewJSONLogger(w io.Writer, opts ...EncoderOption) Logger {
+	return newEncoder(w, encodeJSON, opts...)
+}
+
+func encodeJSON(buf *bytes.Buffer, fields []field) {
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONString(buf, f.key)
+		buf.WriteByte(':')
+		writeJSONValue(buf, f.value)
+	}
+	buf.WriteString("}\n")
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	// a string always marshals
+	data, _ := json.Marshal(s)
+	buf.Write(data)
+}
+
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case time.Time:
+		writeJSONString(buf, v.Format(time.RFC3339Nano))
+		return
+	case []byte:
+		writeJSONString(buf, string(v))
+		return
+	case json.Marshaler:
+		// the marshaler takes precedence over the error and Stringer text
+	case error:
+		writeJSONString(buf, safeString(v, "Error", v.Error))
+		return
+	case fmt.Stringer:
+		writeJSONString(buf, safeString(v, "String", v.String))
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeJSONString(buf, fmt.Sprintf("%+v", value))
+		return
+	}
+	buf.Write(data)
+}
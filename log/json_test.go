@@ -0,0 +1,81 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type testStringer struct{}
+
+func (testStringer) String() string { return "stringer" }
+
+func TestJSONLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewJSONLogger(buffer, withNow(testTime))
+
+	_ = logger.Log(LevelInfo, "user", "kratos", "msg", "hello \"world\"\n")
+	_ = logger.Log(LevelError,
+		"err", errors.New("failed"),
+		"num", 1,
+		"nested", map[string]int{"a": 1},
+		"stringer", testStringer{},
+		"bytes", []byte("raw"),
+		"time", testTime,
+		"nil", nil,
+		"func", func() {},
+	)
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected lines: %q", lines)
+	}
+	if want := `{"ts":"2022-02-13T05:09:20.123456789Z","level":"INFO","msg":"hello \"world\"\n","user":"kratos"}`; lines[0] != want {
+		t.Errorf("Expected: %v, got: %v", want, lines[0])
+	}
+	// the value failing to marshal is written as its fmt string
+	if want := `{"ts":"2022-02-13T05:09:20.123456789Z","level":"ERROR","err":"failed","num":1,"nested":{"a":1},"stringer":"stringer","bytes":"raw","time":"2022-02-13T05:09:20.123456789Z","nil":null,"func":"0x`; !strings.HasPrefix(lines[1], want) {
+		t.Errorf("Expected prefix: %v, got: %v", want, lines[1])
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("invalid json: %s", line)
+		}
+	}
+}
+
+func TestJSONLogger_Keys(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewJSONLogger(buffer, TimeKey("time"), LevelKey("severity"), MessageKey("message"), withNow(testTime))
+	NewHelper(logger).Info("hello")
+	if got, want := buffer.String(), `{"time":"2022-02-13T05:09:20.123456789Z","severity":"INFO","message":"hello"}`+"\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+}
+
+type panicStringer struct{}
+
+func (panicStringer) String() string { panic("boom") }
+
+func TestJSONLogger_NilValues(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewJSONLogger(buffer, TimeKey(""))
+	var err *testError
+	_ = logger.Log(LevelInfo, "url", (*url.URL)(nil), "err", err, "panic", panicStringer{})
+	if got, want := buffer.String(), `{"level":"INFO","url":"\u003cnil\u003e","err":"\u003cnil\u003e","panic":"%!v(PANIC=String method: boom)"}`+"\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+}
+
+func TestJSONLogger_Collision(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewJSONLogger(buffer, withNow(testTime))
+	_ = logger.Log(LevelInfo, "level", "user", "ts", 1, "msg", "hello", "msg", "again")
+	want := `{"ts":"2022-02-13T05:09:20.123456789Z","level":"INFO","msg":"hello","fields.level":"user","fields.ts":1,"fields.msg":"again"}` + "\n"
+	if got := buffer.String(); got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+}
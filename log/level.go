@@ -23,7 +23,7 @@ func (l Level) String() string {
 	case LevelDebug:
 		return "DEBUG"
 	case LevelInfo:
-		return "LEVEL"
+		return "INFO"
 	case LevelWarn:
 		return "WARN"
 	case LevelError:
@@ -51,4 +51,4 @@ func ParseLevel(s string) Level {
 	default:
 		return LevelInfo
 	}
-}
\ No newline at end of file
+}
@@ -1,8 +1,63 @@
 package log
 
-import "log"
+import (
+	"context"
+	"log"
+)
 
+// DefaultLogger is default logger.
 var DefaultLogger = NewStdLogger(log.Writer())
+
+// Logger is a logger interface.
 type Logger interface {
 	Log(level Level, kvs ...interface{}) error
-}
\ No newline at end of file
+}
+
+type logger struct {
+	logger    Logger
+	prefix    []interface{}
+	hasValuer bool
+	ctx       context.Context
+}
+
+func (c *logger) Log(level Level, kvs ...interface{}) error {
+	all := make([]interface{}, 0, len(c.prefix)+len(kvs))
+	all = append(all, c.prefix...)
+	if c.hasValuer {
+		bindValues(c.ctx, all)
+	}
+	all = append(all, kvs...)
+	return c.logger.Log(level, all...)
+}
+
+// With with logger fields, the Valuer values are evaluated at log time.
+func With(l Logger, kv ...interface{}) Logger {
+	c, ok := l.(*logger)
+	if !ok {
+		return &logger{logger: l, prefix: kv, hasValuer: containsValuer(kv), ctx: context.Background()}
+	}
+	kvs := make([]interface{}, 0, len(c.prefix)+len(kv))
+	kvs = append(kvs, c.prefix...)
+	kvs = append(kvs, kv...)
+	return &logger{
+		logger:    c.logger,
+		prefix:    kvs,
+		hasValuer: containsValuer(kvs),
+		ctx:       c.ctx,
+	}
+}
+
+// WithContext returns a shallow copy of l with its context changed to ctx,
+// which is passed to the Valuer values. The provided ctx must be non-nil.
+func WithContext(ctx context.Context, l Logger) Logger {
+	c, ok := l.(*logger)
+	if !ok {
+		return &logger{logger: l, ctx: ctx}
+	}
+	return &logger{
+		logger:    c.logger,
+		prefix:    c.prefix,
+		hasValuer: c.hasValuer,
+		ctx:       ctx,
+	}
+}
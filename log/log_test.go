@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	calls := 0
+	counter := Valuer(func(context.Context) interface{} {
+		calls++
+		return calls
+	})
+	logger := With(NewStdLogger(buffer), "service", "demo", "count", counter)
+	if calls != 0 {
+		t.Fatalf("the valuer is evaluated before logging")
+	}
+	_ = logger.Log(LevelInfo, "msg", "first")
+	_ = With(logger, "request_id", "1").Log(LevelWarn, "msg", "second")
+
+	expected := []string{
+		"INFO service=demo count=1 msg=first",
+		"WARN service=demo count=2 request_id=1 msg=second",
+		"",
+	}
+	if got := buffer.String(); got != strings.Join(expected, "\n") {
+		t.Errorf("Expected: %v, got: %v", strings.Join(expected, "\n"), got)
+	}
+}
+
+type ctxKey struct{}
+
+func TestWithContext(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	traceID := Valuer(func(ctx context.Context) interface{} {
+		if v, ok := ctx.Value(ctxKey{}).(string); ok {
+			return v
+		}
+		return ""
+	})
+	logger := With(NewStdLogger(buffer), "trace_id", traceID)
+	_ = WithContext(context.WithValue(context.Background(), ctxKey{}, "abc"), logger).Log(LevelInfo, "msg", "traced")
+	_ = logger.Log(LevelInfo, "msg", "untraced")
+	// the context is kept by the later With
+	_ = With(WithContext(context.WithValue(context.Background(), ctxKey{}, "def"), logger), "k", "v").Log(LevelInfo)
+
+	expected := []string{
+		"INFO trace_id=abc msg=traced",
+		"INFO trace_id= msg=untraced",
+		"INFO trace_id=def k=v",
+		"",
+	}
+	if got := buffer.String(); got != strings.Join(expected, "\n") {
+		t.Errorf("Expected: %v, got: %v", strings.Join(expected, "\n"), got)
+	}
+}
@@ -0,0 +1,122 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NewLogfmtLogger new a logger writing the entries in the logfmt format,
+// the values with spaces, quotes, "=" or control characters are quoted and escaped.
+//
+//	ts=2006-01-02T15:04:05.999999999Z07:00 level=INFO msg="hello world" user=kratos
+func NewLogfmtLogger(w io.Writer, opts ...EncoderOption) Logger {
+	return newEncoder(w, encodeLogfmt, opts...)
+}
+
+func encodeLogfmt(buf *bytes.Buffer, fields []field) {
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		writeLogfmtKey(buf, f.key)
+		buf.WriteByte('=')
+		writeLogfmtValue(buf, f.value)
+	}
+	buf.WriteByte('\n')
+}
+
+// writeLogfmtKey writes the key with the invalid characters replaced by "_".
+func writeLogfmtKey(buf *bytes.Buffer, key string) {
+	if key == "" {
+		buf.WriteByte('_')
+		return
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		buf.WriteRune(r)
+	}
+}
+
+func writeLogfmtValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeLogfmtString(buf, v)
+	case []byte:
+		writeLogfmtString(buf, string(v))
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case uint:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(v, 10))
+	case uint32:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case float32:
+		buf.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case time.Time:
+		buf.WriteString(v.Format(time.RFC3339Nano))
+	case error:
+		writeLogfmtString(buf, safeString(v, "Error", v.Error))
+	case fmt.Stringer:
+		writeLogfmtString(buf, safeString(v, "String", v.String))
+	default:
+		writeLogfmtString(buf, fmt.Sprint(v))
+	}
+}
+
+func writeLogfmtString(buf *bytes.Buffer, s string) {
+	if !needsQuote(s) {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r == utf8.RuneError || !unicode.IsPrint(r) {
+				_, _ = fmt.Fprintf(buf, `\u%04x`, r)
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
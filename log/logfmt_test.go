@@ -0,0 +1,75 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2022, 2, 13, 5, 9, 20, 123456789, time.UTC)
+
+func withNow(now time.Time) EncoderOption {
+	return func(o *encoderOptions) {
+		o.now = func() time.Time { return now }
+	}
+}
+
+func TestLogfmtLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewLogfmtLogger(buffer, withNow(testTime))
+
+	_ = logger.Log(LevelInfo, "user", "kratos", "msg", "hello world")
+	_ = logger.Log(LevelWarn,
+		"empty", "",
+		"quote", `say "hi"`,
+		"multi", "line1\nline2\ttab",
+		"eq", "a=b",
+		"nil", nil,
+		"err", errors.New("failed: io"),
+		"num", 3.5,
+		"time", testTime,
+		"bytes", []byte("raw"),
+		"bad key", "v",
+		"ctrl", "\x01",
+	)
+	_ = logger.Log(LevelError, "unpaired")
+	_ = logger.Log(LevelError)
+
+	expected := []string{
+		`ts=2022-02-13T05:09:20.123456789Z level=INFO msg="hello world" user=kratos`,
+		`ts=2022-02-13T05:09:20.123456789Z level=WARN empty="" quote="say \"hi\"" multi="line1\nline2\ttab" eq="a=b" nil=null err="failed: io" num=3.5 time=2022-02-13T05:09:20.123456789Z bytes=raw bad_key=v ctrl="\u0001"`,
+		`ts=2022-02-13T05:09:20.123456789Z level=ERROR unpaired="KEY_VALUES UNPAIRED"`,
+		"",
+	}
+	if got := buffer.String(); got != strings.Join(expected, "\n") {
+		t.Errorf("Expected: %v, got: %v", strings.Join(expected, "\n"), got)
+	}
+}
+
+func TestLogfmtLogger_Keys(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewLogfmtLogger(buffer, TimeKey(""), LevelKey("severity"), MessageKey("message"))
+	NewHelper(logger).Infow("k", "v", "msg", "hello")
+	if got, want := buffer.String(), "severity=INFO message=hello k=v\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+}
+
+type testError struct {
+	msg string
+}
+
+func (e *testError) Error() string { return e.msg }
+
+func TestLogfmtLogger_NilValues(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewLogfmtLogger(buffer, TimeKey(""))
+	var err *testError
+	_ = logger.Log(LevelInfo, "url", (*url.URL)(nil), "err", err, "level", "user")
+	if got, want := buffer.String(), "level=INFO url=<nil> err=<nil> fields.level=user\n"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultCaller is a Valuer that returns the file and line of the first caller outside this package.
+	DefaultCaller Valuer = callerOutsidePackage
+
+	// DefaultTimestamp is a Valuer that returns the current wallclock time.
+	DefaultTimestamp = Timestamp(time.RFC3339)
+
+	// packageDir is the directory of this package, to skip its frames in DefaultCaller.
+	packageDir = func() string {
+		_, file, _, _ := runtime.Caller(0)
+		return filepath.Dir(file)
+	}()
+)
+
+// Valuer is returns a log value, it is evaluated with the context of the logger at log time.
+type Valuer func(ctx context.Context) interface{}
+
+// Value return the function value.
+func Value(ctx context.Context, v interface{}) interface{} {
+	if v, ok := v.(Valuer); ok {
+		return v(ctx)
+	}
+	return v
+}
+
+// Caller returns a Valuer that returns the file and line of the caller at the depth,
+// the depth 0 is the Valuer itself.
+func Caller(depth int) Valuer {
+	return func(context.Context) interface{} {
+		_, file, line, _ := runtime.Caller(depth)
+		return shortFile(file, line)
+	}
+}
+
+func callerOutsidePackage(context.Context) interface{} {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return shortFile(frame.File, frame.Line)
+		}
+		if !more {
+			return shortFile(frame.File, frame.Line)
+		}
+	}
+}
+
+func shortFile(file string, line int) string {
+	idx := strings.LastIndexByte(file, '/')
+	return file[idx+1:] + ":" + strconv.Itoa(line)
+}
+
+// Timestamp returns a timestamp Valuer with a custom time format.
+func Timestamp(layout string) Valuer {
+	return func(context.Context) interface{} {
+		return time.Now().Format(layout)
+	}
+}
+
+func bindValues(ctx context.Context, kvs []interface{}) {
+	for i := 1; i < len(kvs); i += 2 {
+		if v, ok := kvs[i].(Valuer); ok {
+			kvs[i] = v(ctx)
+		}
+	}
+}
+
+func containsValuer(kvs []interface{}) bool {
+	for i := 1; i < len(kvs); i += 2 {
+		if _, ok := kvs[i].(Valuer); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValue(t *testing.T) {
+	if v := Value(context.Background(), "plain"); v != "plain" {
+		t.Errorf("unexpected value %v", v)
+	}
+	ts, ok := Value(context.Background(), Timestamp(time.RFC3339Nano)).(string)
+	if !ok {
+		t.Fatalf("unexpected timestamp type")
+	}
+	if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		t.Errorf("unexpected timestamp %q: %v", ts, err)
+	}
+	if v := Value(context.Background(), Caller(2)); !strings.HasPrefix(v.(string), "value_test.go:") {
+		t.Errorf("unexpected caller %v", v)
+	}
+}
+
+func TestDefaultCaller(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := With(NewStdLogger(buffer), "caller", DefaultCaller)
+	h := NewHelper(logger)
+	SetLogger(logger)
+	defer SetLogger(DefaultLogger)
+
+	_, line := currentLine()
+	h.Info("helper")
+	Infof("global")
+	_ = logger.Log(LevelInfo, "msg", "logger")
+
+	expected := []string{
+		"INFO caller=value_test.go:" + strconv.Itoa(line+1) + " msg=helper",
+		"INFO caller=value_test.go:" + strconv.Itoa(line+2) + " msg=global",
+		"INFO caller=value_test.go:" + strconv.Itoa(line+3) + " msg=logger",
+		"",
+	}
+	if got := buffer.String(); got != strings.Join(expected, "\n") {
+		t.Errorf("Expected: %v, got: %v", strings.Join(expected, "\n"), got)
+	}
+}
+
+func currentLine() (string, int) {
+	v := Caller(2)(context.Background()).(string)
+	idx := strings.LastIndexByte(v, ':')
+	line, _ := strconv.Atoi(v[idx+1:])
+	return v[:idx], line
+}
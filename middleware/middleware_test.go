@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req interface{}) (interface{}, error) {
+				calls = append(calls, name+" before")
+				reply, err := next(ctx, req)
+				calls = append(calls, name+" after")
+				return reply, err
+			}
+		}
+	}
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return req, nil
+	}
+	reply, err := Chain(trace("a"), trace("b"))(h)(context.Background(), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "hello" {
+		t.Errorf("expected hello, got %v", reply)
+	}
+	expected := []string{"a before", "b before", "handler", "b after", "a after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
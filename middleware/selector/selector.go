@@ -0,0 +1,84 @@
+package selector
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"regexp"
+	"strings"
+)
+
+// Builder is a selector builder, the middleware only applies to the
+// operations matching one of the configured paths, prefixes or regexes.
+type Builder struct {
+	path   []string
+	prefix []string
+	regex  []string
+	ms     []middleware.Middleware
+}
+
+// Server selector middleware.
+func Server(ms ...middleware.Middleware) *Builder {
+	return &Builder{ms: ms}
+}
+
+// Path is used to match the operation exactly.
+func (b *Builder) Path(path ...string) *Builder {
+	b.path = append(b.path, path...)
+	return b
+}
+
+// Prefix is used to match the operation by prefix.
+func (b *Builder) Prefix(prefix ...string) *Builder {
+	b.prefix = append(b.prefix, prefix...)
+	return b
+}
+
+// Regex is used to match the operation by regular expression.
+func (b *Builder) Regex(regex ...string) *Builder {
+	b.regex = append(b.regex, regex...)
+	return b
+}
+
+// Build builds the selector middleware, it panics if a regex can not be compiled.
+func (b *Builder) Build() middleware.Middleware {
+	path := make(map[string]struct{}, len(b.path))
+	for _, p := range b.path {
+		path[p] = struct{}{}
+	}
+	prefix := append([]string(nil), b.prefix...)
+	regex := make([]*regexp.Regexp, 0, len(b.regex))
+	for _, r := range b.regex {
+		regex = append(regex, regexp.MustCompile(r))
+	}
+	return selector(func(operation string) bool {
+		if _, ok := path[operation]; ok {
+			return true
+		}
+		for _, p := range prefix {
+			if strings.HasPrefix(operation, p) {
+				return true
+			}
+		}
+		for _, r := range regex {
+			if r.MatchString(operation) {
+				return true
+			}
+		}
+		return false
+	}, b.ms...)
+}
+
+func selector(match func(operation string) bool, ms ...middleware.Middleware) middleware.Middleware {
+	chain := middleware.Chain(ms...)
+	return func(next middleware.Handler) middleware.Handler {
+		matched := chain(next)
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok || !match(tr.Operation()) {
+				return next(ctx, req)
+			}
+			return matched(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package selector
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"testing"
+)
+
+type testTransport struct {
+	operation string
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return transport.KindHTTP }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return tr.operation }
+func (tr *testTransport) RequestHeader() transport.Header { return nil }
+func (tr *testTransport) ReplyHeader() transport.Header   { return nil }
+
+func TestSelector(t *testing.T) {
+	mark := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "selected", nil
+		}
+	}
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "handler", nil
+	}
+	m := Server(mark).
+		Path("/v1/users").
+		Prefix("/admin/").
+		Regex(`^/v2/users/[0-9]+$`).
+		Build()
+
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/v1/users", "selected"},
+		{"/v1/users/{id}", "handler"},
+		{"/admin/users", "selected"},
+		{"/v2/users/42", "selected"},
+		{"/v2/users/kratos", "handler"},
+		{"/", "handler"},
+	}
+	for _, tc := range testCases {
+		ctx := transport.NewServerContext(context.Background(), &testTransport{operation: tc.path})
+		reply, err := m(h)(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reply != tc.expected {
+			t.Errorf("%s: expected %s, got %v", tc.path, tc.expected, reply)
+		}
+	}
+
+	reply, _ := m(h)(context.Background(), nil)
+	if reply != "handler" {
+		t.Errorf("expected requests without transport to skip the middleware, got %v", reply)
+	}
+}
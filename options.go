@@ -29,6 +29,12 @@ type options struct {
 	registrar registry.Registrar
 
 	servers []transport.Server
+
+	// Before and After funcs
+	beforeStart []func(context.Context) error
+	beforeStop  []func(context.Context) error
+	afterStart  []func(context.Context) error
+	afterStop   []func(context.Context) error
 }
 
 func ID(id string) Option {
@@ -65,3 +71,31 @@ func Registrar(r registry.Registrar) Option {
 		o.registrar = r
 	}
 }
+
+// BeforeStart runs funcs before app starts, an error aborts the startup.
+func BeforeStart(fn func(context.Context) error) Option {
+	return func(o *options) {
+		o.beforeStart = append(o.beforeStart, fn)
+	}
+}
+
+// BeforeStop runs funcs before app stops.
+func BeforeStop(fn func(context.Context) error) Option {
+	return func(o *options) {
+		o.beforeStop = append(o.beforeStop, fn)
+	}
+}
+
+// AfterStart runs funcs after app starts, an error stops the app.
+func AfterStart(fn func(context.Context) error) Option {
+	return func(o *options) {
+		o.afterStart = append(o.afterStart, fn)
+	}
+}
+
+// AfterStop runs funcs after app stops, they always run during the shutdown.
+func AfterStop(fn func(context.Context) error) Option {
+	return func(o *options) {
+		o.afterStop = append(o.afterStop, fn)
+	}
+}
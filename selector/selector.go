@@ -0,0 +1,48 @@
+package selector
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"math/rand"
+	"sync/atomic"
+)
+
+// ErrNoAvailable is returned when there is no instance to select.
+var ErrNoAvailable = errors.New("no available instance")
+
+// Selector selects a service instance for every client call.
+type Selector interface {
+	Select(ctx context.Context, instances []*registry.ServiceInstance) (*registry.ServiceInstance, error)
+}
+
+// Random returns a selector picking the instances randomly.
+func Random() Selector {
+	return &random{}
+}
+
+type random struct{}
+
+func (*random) Select(_ context.Context, instances []*registry.ServiceInstance) (*registry.ServiceInstance, error) {
+	if len(instances) == 0 {
+		return nil, ErrNoAvailable
+	}
+	return instances[rand.Intn(len(instances))], nil
+}
+
+// RoundRobin returns a selector picking the instances in turn.
+func RoundRobin() Selector {
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	next uint64
+}
+
+func (r *roundRobin) Select(_ context.Context, instances []*registry.ServiceInstance) (*registry.ServiceInstance, error) {
+	if len(instances) == 0 {
+		return nil, ErrNoAvailable
+	}
+	n := atomic.AddUint64(&r.next, 1) - 1
+	return instances[n%uint64(len(instances))], nil
+}
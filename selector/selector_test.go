@@ -0,0 +1,36 @@
+package selector
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"testing"
+)
+
+func TestSelector(t *testing.T) {
+	instances := []*registry.ServiceInstance{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+	for _, s := range []Selector{Random(), RoundRobin()} {
+		seen := make(map[string]int)
+		for i := 0; i < 300; i++ {
+			in, err := s.Select(context.Background(), instances)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen[in.ID]++
+		}
+		if len(seen) != len(instances) {
+			t.Errorf("%T: expected all instances to be selected, got %v", s, seen)
+		}
+		if _, err := s.Select(context.Background(), nil); !errors.Is(err, ErrNoAvailable) {
+			t.Errorf("%T: expected ErrNoAvailable, got %v", s, err)
+		}
+	}
+
+	s := RoundRobin()
+	for i, expected := range []string{"1", "2", "3", "1"} {
+		in, _ := s.Select(context.Background(), instances)
+		if in.ID != expected {
+			t.Errorf("round %d: expected %s, got %s", i, expected, in.ID)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"github.com/tiennampham23/kratos-cloned/transport/grpc/resolver/discovery"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpcinsecure "google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"time"
+)
+
+// ClientOption is gRPC client option.
+type ClientOption func(o *clientOptions)
+
+// WithEndpoint with client endpoint, e.g. 127.0.0.1:9000 or discovery:///service-name.
+func WithEndpoint(endpoint string) ClientOption {
+	return func(o *clientOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithTimeout with client timeout, it is applied as the deadline of every unary call.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithMiddleware with client middleware.
+func WithMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.ms = m
+	}
+}
+
+// WithDiscovery with client discovery, it resolves the discovery:/// endpoints.
+func WithDiscovery(d registry.Discovery) ClientOption {
+	return func(o *clientOptions) {
+		o.discovery = d
+	}
+}
+
+// WithTLSConfig with TLS config, Dial uses an empty TLS config verifying
+// the server against the system roots when it is not set.
+func WithTLSConfig(c *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConf = c
+	}
+}
+
+// WithUnaryInterceptor returns a DialOption that specifies the interceptor for unary RPCs.
+func WithUnaryInterceptor(in ...grpc.UnaryClientInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.ints = in
+	}
+}
+
+// WithStreamInterceptor returns a DialOption that specifies the interceptor for streaming RPCs.
+func WithStreamInterceptor(in ...grpc.StreamClientInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.streamInts = in
+	}
+}
+
+// WithOptions with gRPC options.
+func WithOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *clientOptions) {
+		o.grpcOpts = opts
+	}
+}
+
+// clientOptions is gRPC Client
+type clientOptions struct {
+	endpoint   string
+	tlsConf    *tls.Config
+	timeout    time.Duration
+	discovery  registry.Discovery
+	ms         []middleware.Middleware
+	ints       []grpc.UnaryClientInterceptor
+	streamInts []grpc.StreamClientInterceptor
+	grpcOpts   []grpc.DialOption
+}
+
+// Dial returns a GRPC connection secured by TLS, the TLS config set by
+// WithTLSConfig or an empty one.
+func Dial(ctx context.Context, opts ...ClientOption) (*grpc.ClientConn, error) {
+	return dial(ctx, false, opts...)
+}
+
+// DialInsecure returns an insecure GRPC connection.
+func DialInsecure(ctx context.Context, opts ...ClientOption) (*grpc.ClientConn, error) {
+	return dial(ctx, true, opts...)
+}
+
+func dial(ctx context.Context, insecure bool, opts ...ClientOption) (*grpc.ClientConn, error) {
+	options := clientOptions{
+		timeout: 2000 * time.Millisecond,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	ints := append([]grpc.UnaryClientInterceptor{unaryClientInterceptor(options.ms, options.timeout)}, options.ints...)
+	streamInts := append([]grpc.StreamClientInterceptor{streamClientInterceptor(options.ms)}, options.streamInts...)
+	grpcOpts := []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+		grpc.WithChainUnaryInterceptor(ints...),
+		grpc.WithChainStreamInterceptor(streamInts...),
+	}
+	if options.discovery != nil {
+		grpcOpts = append(grpcOpts, grpc.WithResolvers(discovery.NewBuilder(options.discovery, discovery.WithInsecure(insecure))))
+	}
+	if insecure {
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(grpcinsecure.NewCredentials()))
+	} else {
+		tlsConf := options.tlsConf
+		if tlsConf == nil {
+			tlsConf = &tls.Config{}
+		}
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConf)))
+	}
+	grpcOpts = append(grpcOpts, options.grpcOpts...)
+	return grpc.DialContext(ctx, options.endpoint, grpcOpts...)
+}
+
+// unaryClientInterceptor is a gRPC unary client interceptor running the client middleware.
+func unaryClientInterceptor(ms []middleware.Middleware, timeout time.Duration) grpc.UnaryClientInterceptor {
+	chain := middleware.Chain(ms...)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		tr := &Transport{
+			endpoint:    cc.Target(),
+			operation:   method,
+			reqHeader:   transport.MetadataCarrier{},
+			replyHeader: transport.MetadataCarrier{},
+		}
+		ctx = transport.NewClientContext(ctx, tr)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		h := func(ctx context.Context, req interface{}) (interface{}, error) {
+			ctx = appendOutgoingHeader(ctx, tr.reqHeader)
+			var header metadata.MD
+			err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+			for k, v := range header {
+				tr.replyHeader[k] = v
+			}
+			return reply, err
+		}
+		_, err := chain(h)(ctx, req)
+		return err
+	}
+}
+
+// streamClientInterceptor is a gRPC stream client interceptor running the client
+// middleware when the stream is opened, the reply header is read from the stream.
+func streamClientInterceptor(ms []middleware.Middleware) grpc.StreamClientInterceptor {
+	chain := middleware.Chain(ms...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		tr := &Transport{
+			endpoint:    cc.Target(),
+			operation:   method,
+			reqHeader:   transport.MetadataCarrier{},
+			replyHeader: transport.MetadataCarrier{},
+		}
+		ctx = transport.NewClientContext(ctx, tr)
+		h := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return streamer(appendOutgoingHeader(ctx, tr.reqHeader), desc, cc, method, opts...)
+		}
+		reply, err := chain(h)(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return reply.(grpc.ClientStream), nil
+	}
+}
+
+// appendOutgoingHeader appends the request header to the outgoing metadata.
+func appendOutgoingHeader(ctx context.Context, header transport.MetadataCarrier) context.Context {
+	keyvals := make([]string, 0, len(header))
+	for k, vs := range header {
+		for _, v := range vs {
+			keyvals = append(keyvals, k, v)
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, keyvals...)
+}
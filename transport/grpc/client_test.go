@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+type mockDiscovery struct {
+	instances []*registry.ServiceInstance
+}
+
+func (d *mockDiscovery) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return d.instances, nil
+}
+
+func (d *mockDiscovery) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return &mockWatcher{ctx: ctx, instances: d.instances}, nil
+}
+
+type mockWatcher struct {
+	ctx       context.Context
+	instances []*registry.ServiceInstance
+	sent      bool
+}
+
+func (w *mockWatcher) Next() ([]*registry.ServiceInstance, error) {
+	if !w.sent {
+		w.sent = true
+		return w.instances, nil
+	}
+	<-w.ctx.Done()
+	return nil, w.ctx.Err()
+}
+
+func (w *mockWatcher) Stop() error {
+	return nil
+}
+
+func TestDialInsecure(t *testing.T) {
+	srv := NewServer(Address("127.0.0.1:0"))
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+	u, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &mockDiscovery{instances: []*registry.ServiceInstance{{
+		ID:        "1",
+		Name:      "helloworld",
+		Endpoints: []string{"http://127.0.0.1:8000?isSecure=false", u.String()},
+	}}}
+
+	var operation, endpoint string
+	m := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromClientContext(ctx)
+			if !ok {
+				t.Fatal("expected client transport in context")
+			}
+			operation, endpoint = tr.Operation(), tr.Endpoint()
+			tr.RequestHeader().Set("x-request-id", "1")
+			return next(ctx, req)
+		}
+	}
+	conn, err := DialInsecure(context.Background(),
+		WithEndpoint("discovery:///helloworld"),
+		WithDiscovery(d),
+		WithMiddleware(m),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("expected serving status, got %s", resp.Status)
+	}
+	if operation != "/grpc.health.v1.Health/Check" {
+		t.Errorf("unexpected operation: %s", operation)
+	}
+	if endpoint != "discovery:///helloworld" {
+		t.Errorf("unexpected endpoint: %s", endpoint)
+	}
+}
+
+func TestDial_DefaultTLS(t *testing.T) {
+	srv := NewServer(Address("127.0.0.1:0"))
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+
+	// the secure client refuses the plaintext server instead of dialing it insecurely
+	conn, err := Dial(context.Background(), WithEndpoint(srv.lis.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable && status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected a TLS handshake failure, got %v", err)
+	}
+}
+
+func TestDialInsecure_Stream(t *testing.T) {
+	var requestID string
+	srv := NewServer(Address("127.0.0.1:0"), Options(grpc.StreamInterceptor(
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			md, _ := metadata.FromIncomingContext(ss.Context())
+			if v := md.Get("x-request-id"); len(v) == 1 {
+				requestID = v[0]
+			}
+			return handler(srv, ss)
+		},
+	)))
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+
+	var operation string
+	m := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromClientContext(ctx)
+			if !ok {
+				t.Fatal("expected client transport in context")
+			}
+			operation = tr.Operation()
+			tr.RequestHeader().Set("x-request-id", "1")
+			return next(ctx, req)
+		}
+	}
+	conn, err := DialInsecure(context.Background(), WithEndpoint(srv.lis.Addr().String()), WithMiddleware(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := grpc_health_v1.NewHealthClient(conn).Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if operation != "/grpc.health.v1.Health/Watch" {
+		t.Errorf("unexpected operation: %s", operation)
+	}
+	if requestID != "1" {
+		t.Errorf("expected request header on the stream, got %q", requestID)
+	}
+}
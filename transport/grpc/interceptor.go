@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// unaryServerInterceptor is a gRPC unary server interceptor running the server middleware.
+func (s *Server) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	chain := middleware.Chain(s.ms...)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, tr := s.newServerContext(ctx, info.FullMethod)
+		if s.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+		}
+		h := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return handler(ctx, req)
+		}
+		reply, err := chain(h)(ctx, req)
+		if len(tr.replyHeader) > 0 {
+			_ = grpc.SetHeader(ctx, metadata.MD(tr.replyHeader))
+		}
+		return reply, err
+	}
+}
+
+// streamServerInterceptor is a gRPC stream server interceptor running the server middleware,
+// the middleware receives the grpc.ServerStream as the request. The reply headers are sent
+// with the first message of the handler, or when the call ends without one.
+func (s *Server) streamServerInterceptor() grpc.StreamServerInterceptor {
+	chain := middleware.Chain(s.ms...)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, tr := s.newServerContext(ss.Context(), info.FullMethod)
+		ws := &wrappedStream{ServerStream: ss, ctx: ctx, tr: tr}
+		h := func(ctx context.Context, req interface{}) (interface{}, error) {
+			ws.ctx = ctx
+			return nil, handler(srv, ws)
+		}
+		_, err := chain(h)(ctx, ss)
+		if hErr := ws.setHeader(); hErr != nil && err == nil {
+			err = hErr
+		}
+		return err
+	}
+}
+
+func (s *Server) newServerContext(ctx context.Context, operation string) (context.Context, *Transport) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tr := &Transport{
+		operation:   operation,
+		reqHeader:   transport.MetadataCarrier(md.Copy()),
+		replyHeader: transport.MetadataCarrier{},
+	}
+	if s.endpoint != nil {
+		tr.endpoint = s.endpoint.String()
+	}
+	ctx = &baseContext{Context: ctx, base: s.baseCtx}
+	return transport.NewServerContext(ctx, tr), tr
+}
+
+// baseContext looks up the values missing in the call context from the
+// context passed to Start, e.g. the AppInfo injected by App.Run.
+type baseContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c *baseContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.base.Value(key)
+}
+
+// wrappedStream rewrites the context of a grpc.ServerStream, and sets the
+// reply headers of the transport before the first header or message is sent.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	tr         *Transport
+	headerSent bool
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+func (w *wrappedStream) setHeader() error {
+	if w.headerSent {
+		return nil
+	}
+	w.headerSent = true
+	if len(w.tr.replyHeader) == 0 {
+		return nil
+	}
+	return w.ServerStream.SetHeader(metadata.MD(w.tr.replyHeader))
+}
+
+func (w *wrappedStream) SendHeader(md metadata.MD) error {
+	if err := w.setHeader(); err != nil {
+		return err
+	}
+	return w.ServerStream.SendHeader(md)
+}
+
+func (w *wrappedStream) SendMsg(m interface{}) error {
+	if err := w.setHeader(); err != nil {
+		return err
+	}
+	return w.ServerStream.SendMsg(m)
+}
@@ -0,0 +1,93 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"google.golang.org/grpc/resolver"
+	"strings"
+	"time"
+)
+
+const name = "discovery"
+
+// Option is builder option.
+type Option func(o *builder)
+
+// WithTimeout with timeout option.
+func WithTimeout(timeout time.Duration) Option {
+	return func(b *builder) {
+		b.timeout = timeout
+	}
+}
+
+// WithInsecure with isSecure option, only the insecure endpoints are resolved.
+func WithInsecure(insecure bool) Option {
+	return func(b *builder) {
+		b.insecure = insecure
+	}
+}
+
+type builder struct {
+	discoverer registry.Discovery
+	timeout    time.Duration
+	insecure   bool
+}
+
+// NewBuilder creates a builder which is used to factory registry resolvers
+// for the discovery:///service-name targets.
+func NewBuilder(d registry.Discovery, opts ...Option) resolver.Builder {
+	b := &builder{
+		discoverer: d,
+		timeout:    10 * time.Second,
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+func (b *builder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	type result struct {
+		w   registry.Watcher
+		err error
+	}
+	// buffered, the watcher goroutine never blocks after a timeout
+	done := make(chan result, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		w, err := b.discoverer.Watch(ctx, strings.TrimPrefix(target.URL.Path, "/"))
+		done <- result{w: w, err: err}
+	}()
+	var res result
+	select {
+	case res = <-done:
+	case <-time.After(b.timeout):
+		cancel()
+		go func() {
+			// stop the watcher created after the timeout
+			if res := <-done; res.err == nil && res.w != nil {
+				_ = res.w.Stop()
+			}
+		}()
+		return nil, errors.New("discovery create watcher overtime")
+	}
+	if res.err != nil {
+		cancel()
+		return nil, res.err
+	}
+	r := &discoveryResolver{
+		w:        res.w,
+		cc:       cc,
+		ctx:      ctx,
+		cancel:   cancel,
+		insecure: b.insecure,
+	}
+	go r.watch()
+	return r, nil
+}
+
+// Scheme return scheme of discovery
+func (*builder) Scheme() string {
+	return name
+}
@@ -0,0 +1,90 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/internal/endpoint"
+	"github.com/tiennampham23/kratos-cloned/log"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+	"time"
+)
+
+type discoveryResolver struct {
+	w  registry.Watcher
+	cc resolver.ClientConn
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	insecure bool
+}
+
+func (r *discoveryResolver) watch() {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		default:
+		}
+		ins, err := r.w.Next()
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			log.Errorf("[resolver] Failed to watch discovery endpoint: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		r.update(ins)
+	}
+}
+
+func (r *discoveryResolver) update(ins []*registry.ServiceInstance) {
+	addrs := make([]resolver.Address, 0, len(ins))
+	endpoints := make(map[string]struct{}, len(ins))
+	for _, in := range ins {
+		ept, err := endpoint.ParseEndpoint(in.Endpoints, "grpc", !r.insecure)
+		if err != nil {
+			log.Errorf("[resolver] Failed to parse discovery endpoint: %v", err)
+			continue
+		}
+		if ept == "" {
+			continue
+		}
+		if _, ok := endpoints[ept]; ok {
+			continue
+		}
+		endpoints[ept] = struct{}{}
+		addrs = append(addrs, resolver.Address{
+			ServerName: in.Name,
+			Attributes: parseAttributes(in.Metadata).WithValue("rawServiceInstance", in),
+			Addr:       ept,
+		})
+	}
+	if len(addrs) == 0 {
+		log.Errorf("[resolver] Zero endpoint found, refused to write, instances: %v", ins)
+		return
+	}
+	if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		log.Errorf("[resolver] failed to update state: %v", err)
+	}
+}
+
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	if err := r.w.Stop(); err != nil {
+		log.Errorf("[resolver] failed to stop the watcher: %v", err)
+	}
+}
+
+func (r *discoveryResolver) ResolveNow(options resolver.ResolveNowOptions) {}
+
+func parseAttributes(md map[string]string) *attributes.Attributes {
+	var a *attributes.Attributes
+	for k, v := range md {
+		a = a.WithValue(k, v)
+	}
+	return a
+}
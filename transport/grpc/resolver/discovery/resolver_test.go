@@ -0,0 +1,178 @@
+package discovery
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"google.golang.org/grpc/resolver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testClientConn struct {
+	resolver.ClientConn
+	states  []resolver.State
+	updated chan resolver.State
+}
+
+func (cc *testClientConn) UpdateState(s resolver.State) error {
+	if cc.updated != nil {
+		cc.updated <- s
+		return nil
+	}
+	cc.states = append(cc.states, s)
+	return nil
+}
+
+func TestResolver_Update(t *testing.T) {
+	testCases := []struct {
+		insecure bool
+		expected []string
+	}{
+		{true, []string{"127.0.0.1:9000", "127.0.0.2:9000"}},
+		{false, []string{"127.0.0.1:9443"}},
+	}
+	instances := []*registry.ServiceInstance{
+		{
+			Name:     "helloworld",
+			Metadata: map[string]string{"region": "sh"},
+			Endpoints: []string{
+				"http://127.0.0.1:8000?isSecure=false",
+				"grpc://127.0.0.1:9000?isSecure=false",
+				"grpc://127.0.0.1:9443?isSecure=true",
+			},
+		},
+		{Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000?isSecure=false"}},
+		{Name: "helloworld", Endpoints: []string{"grpc://127.0.0.2:9000?isSecure=false"}},
+		{Name: "helloworld", Endpoints: []string{"http://127.0.0.3:8000?isSecure=false"}},
+	}
+	for _, tc := range testCases {
+		cc := &testClientConn{}
+		r := &discoveryResolver{cc: cc, insecure: tc.insecure}
+		r.update(instances)
+		if len(cc.states) != 1 {
+			t.Fatalf("expected one state update, got %d", len(cc.states))
+		}
+		addrs := make([]string, 0)
+		for _, a := range cc.states[0].Addresses {
+			addrs = append(addrs, a.Addr)
+			if a.Attributes.Value("rawServiceInstance") == nil {
+				t.Errorf("expected raw service instance attribute for %s", a.Addr)
+			}
+		}
+		if !reflect.DeepEqual(addrs, tc.expected) {
+			t.Errorf("insecure %v: expected %v, got %v", tc.insecure, tc.expected, addrs)
+		}
+		if v := cc.states[0].Addresses[0].Attributes.Value("region"); v != "sh" {
+			t.Errorf("expected metadata attribute, got %v", v)
+		}
+	}
+
+	cc := &testClientConn{}
+	(&discoveryResolver{cc: cc, insecure: true}).update(instances[3:])
+	if len(cc.states) != 0 {
+		t.Error("expected no update without grpc endpoints")
+	}
+}
+
+type testWatcher struct {
+	ch      chan []*registry.ServiceInstance
+	ctx     context.Context
+	stopped bool
+}
+
+func (w *testWatcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case ins := <-w.ch:
+		return ins, nil
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	}
+}
+
+func (w *testWatcher) Stop() error {
+	w.stopped = true
+	return nil
+}
+
+type testDiscovery struct {
+	w     *testWatcher
+	name  string
+	delay time.Duration
+}
+
+func (d *testDiscovery) GetService(ctx context.Context, name string) ([]*registry.ServiceInstance, error) {
+	return nil, nil
+}
+
+func (d *testDiscovery) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	time.Sleep(d.delay)
+	d.name = name
+	d.w.ctx = ctx
+	return d.w, nil
+}
+
+func TestBuilder(t *testing.T) {
+	d := &testDiscovery{w: &testWatcher{ch: make(chan []*registry.ServiceInstance)}}
+	b := NewBuilder(d, WithInsecure(true))
+	if b.Scheme() != "discovery" {
+		t.Errorf("unexpected scheme: %s", b.Scheme())
+	}
+	cc := &testClientConn{updated: make(chan resolver.State, 1)}
+	target := resolver.Target{}
+	target.URL.Scheme = "discovery"
+	target.URL.Path = "/helloworld"
+	r, err := b.Build(target, cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.name != "helloworld" {
+		t.Errorf("expected helloworld service, got %s", d.name)
+	}
+	for _, addr := range []string{"127.0.0.1:9000", "127.0.0.1:9001"} {
+		d.w.ch <- []*registry.ServiceInstance{{Name: "helloworld", Endpoints: []string{"grpc://" + addr + "?isSecure=false"}}}
+		if s := <-cc.updated; len(s.Addresses) != 1 || s.Addresses[0].Addr != addr {
+			t.Errorf("expected %s, got %v", addr, s.Addresses)
+		}
+	}
+	r.Close()
+	if !d.w.stopped {
+		t.Error("expected watcher to be stopped")
+	}
+}
+
+func TestBuilder_Timeout(t *testing.T) {
+	stopped := make(chan struct{})
+	d := &testDiscovery{w: &testWatcher{}, delay: 50 * time.Millisecond}
+	b := NewBuilder(&stopDiscovery{testDiscovery: d, stopped: stopped}, WithTimeout(10*time.Millisecond))
+	target := resolver.Target{}
+	target.URL.Path = "/helloworld"
+	if _, err := b.Build(target, &testClientConn{}, resolver.BuildOptions{}); err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("expected the late watcher to be stopped")
+	}
+}
+
+type stopDiscovery struct {
+	*testDiscovery
+	stopped chan struct{}
+}
+
+func (d *stopDiscovery) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	w, err := d.testDiscovery.Watch(ctx, name)
+	return &stopWatcher{Watcher: w, stopped: d.stopped}, err
+}
+
+type stopWatcher struct {
+	registry.Watcher
+	stopped chan struct{}
+}
+
+func (w *stopWatcher) Stop() error {
+	close(w.stopped)
+	return w.Watcher.Stop()
+}
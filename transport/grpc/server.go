@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/tiennampham23/kratos-cloned/internal/endpoint"
+	"github.com/tiennampham23/kratos-cloned/internal/host"
+	"github.com/tiennampham23/kratos-cloned/log"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"net"
+	"net/url"
+	"time"
+)
+
+// Server is a gRPC server wrapper.
+type Server struct {
+	*grpc.Server
+	baseCtx    context.Context
+	lis        net.Listener
+	tlsConf    *tls.Config
+	endpoint   *url.URL
+	err        error
+	network    string
+	address    string
+	timeout    time.Duration
+	ms         []middleware.Middleware
+	unaryInts  []grpc.UnaryServerInterceptor
+	streamInts []grpc.StreamServerInterceptor
+	grpcOpts   []grpc.ServerOption
+	health     *health.Server
+}
+
+// ServerOption is a gRPC server option.
+type ServerOption func(*Server)
+
+// Network with server network.
+func Network(network string) ServerOption {
+	return func(s *Server) {
+		s.network = network
+	}
+}
+
+// Address with server address.
+func Address(addr string) ServerOption {
+	return func(s *Server) {
+		s.address = addr
+	}
+}
+
+// Timeout with server timeout, it is applied as the deadline of every unary call.
+func Timeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.timeout = timeout
+	}
+}
+
+// TLSConfig with TLS config.
+func TLSConfig(c *tls.Config) ServerOption {
+	return func(s *Server) {
+		s.tlsConf = c
+	}
+}
+
+// Listener with server lis.
+func Listener(lis net.Listener) ServerOption {
+	return func(s *Server) {
+		s.lis = lis
+	}
+}
+
+// Middleware with server middleware, it is applied to every unary and stream call.
+func Middleware(m ...middleware.Middleware) ServerOption {
+	return func(s *Server) {
+		s.ms = m
+	}
+}
+
+// UnaryInterceptor returns a ServerOption that sets the UnaryServerInterceptor for the server.
+func UnaryInterceptor(in ...grpc.UnaryServerInterceptor) ServerOption {
+	return func(s *Server) {
+		s.unaryInts = in
+	}
+}
+
+// StreamInterceptor returns a ServerOption that sets the StreamServerInterceptor for the server.
+func StreamInterceptor(in ...grpc.StreamServerInterceptor) ServerOption {
+	return func(s *Server) {
+		s.streamInts = in
+	}
+}
+
+// Options with grpc options.
+func Options(opts ...grpc.ServerOption) ServerOption {
+	return func(s *Server) {
+		s.grpcOpts = opts
+	}
+}
+
+// NewServer creates a gRPC server by options.
+func NewServer(opts ...ServerOption) *Server {
+	srv := &Server{
+		baseCtx: context.Background(),
+		network: "tcp",
+		address: ":0",
+		timeout: 1 * time.Second,
+		health:  health.NewServer(),
+	}
+	for _, o := range opts {
+		o(srv)
+	}
+	unaryInts := append([]grpc.UnaryServerInterceptor{srv.unaryServerInterceptor()}, srv.unaryInts...)
+	streamInts := append([]grpc.StreamServerInterceptor{srv.streamServerInterceptor()}, srv.streamInts...)
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInts...),
+		grpc.ChainStreamInterceptor(streamInts...),
+	}
+	if srv.tlsConf != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(srv.tlsConf)))
+	}
+	grpcOpts = append(grpcOpts, srv.grpcOpts...)
+	srv.Server = grpc.NewServer(grpcOpts...)
+	srv.err = srv.listenAndEndpoint()
+	// internal register
+	grpc_health_v1.RegisterHealthServer(srv.Server, srv.health)
+	reflection.Register(srv.Server)
+	return srv
+}
+
+// Endpoint return a real address to registry endpoint, it is nil for a non-TCP listener.
+// examples:
+//
+//	grpc://127.0.0.1:9000?isSecure=false
+func (s *Server) Endpoint() (*url.URL, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.endpoint, nil
+}
+
+// Start start the gRPC server.
+func (s *Server) Start(ctx context.Context) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.baseCtx = ctx
+	log.Infof("[gRPC] server listening on: %s", s.lis.Addr().String())
+	s.health.Resume()
+	return s.Serve(s.lis)
+}
+
+// Stop gracefully stops the gRPC server, waiting for the pending calls to
+// complete until the ctx deadline, then stops it forcibly and returns the ctx error.
+func (s *Server) Stop(ctx context.Context) error {
+	log.Info("[gRPC] server stopping")
+	s.health.Shutdown()
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warnf("[gRPC] server forced to stop: %v", ctx.Err())
+		s.Server.Stop()
+		<-done
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (s *Server) listenAndEndpoint() error {
+	// the host of a supplied listener is the one it is bound to, not the address option
+	hostPort, created := s.address, s.lis == nil
+	if created {
+		lis, err := net.Listen(s.network, s.address)
+		if err != nil {
+			return err
+		}
+		s.lis = lis
+	} else {
+		hostPort = s.lis.Addr().String()
+	}
+	// only a TCP listener has a registry endpoint, e.g. a bufconn one has none
+	if _, ok := host.Port(s.lis); !ok {
+		return nil
+	}
+	addr, err := host.Extract(hostPort, s.lis)
+	if err != nil {
+		// a supplied listener is left to the caller
+		if created {
+			_ = s.lis.Close()
+		}
+		return err
+	}
+	s.endpoint = endpoint.NewEndpoint("grpc", addr, s.tlsConf != nil)
+	return nil
+}
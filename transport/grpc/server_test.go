@@ -0,0 +1,298 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/tiennampham23/kratos-cloned/errors"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testKey struct{}
+
+func TestServer(t *testing.T) {
+	var (
+		lk         sync.Mutex
+		operations []string
+	)
+	m := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				t.Error("expected transport in context")
+				return next(ctx, req)
+			}
+			if tr.Kind() != transport.KindGRPC {
+				t.Errorf("expected grpc kind, got %s", tr.Kind())
+			}
+			if v, _ := ctx.Value(testKey{}).(string); v != "kratos" {
+				t.Errorf("expected base context value, got %q", v)
+			}
+			if tr.RequestHeader().Get("x-request-id") != "1" {
+				t.Errorf("unexpected request header: %v", tr.RequestHeader().Keys())
+			}
+			tr.ReplyHeader().Set("x-reply-id", "2")
+			lk.Lock()
+			operations = append(operations, tr.Operation())
+			lk.Unlock()
+			return next(ctx, req)
+		}
+	}
+	srv := NewServer(Address("127.0.0.1:0"), Middleware(m))
+	ctx := context.WithValue(context.Background(), testKey{}, "kratos")
+	go func() {
+		if err := srv.Start(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+
+	conn, err := grpc.Dial(srv.lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+	callCtx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "1")
+
+	var header metadata.MD
+	resp, err := client.Check(callCtx, &grpc_health_v1.HealthCheckRequest{}, grpc.Header(&header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("expected serving status, got %s", resp.Status)
+	}
+	if v := header.Get("x-reply-id"); len(v) != 1 || v[0] != "2" {
+		t.Errorf("expected reply header, got %v", header)
+	}
+
+	stream, err := client.Watch(callCtx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if header, err = stream.Header(); err != nil || len(header.Get("x-reply-id")) != 1 {
+		t.Errorf("expected stream reply header, got %v %v", header, err)
+	}
+
+	lk.Lock()
+	defer lk.Unlock()
+	expected := []string{"/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Watch"}
+	if fmt.Sprint(operations) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, operations)
+	}
+}
+
+func TestServer_Endpoint(t *testing.T) {
+	srv := NewServer(Address("127.0.0.1:0"))
+	defer srv.lis.Close()
+	u, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("grpc://%s?isSecure=false", srv.lis.Addr().String())
+	if u.String() != expected {
+		t.Errorf("expected %s, got %s", expected, u)
+	}
+
+	tlsSrv := NewServer(Address(":0"), TLSConfig(&tls.Config{}))
+	defer tlsSrv.lis.Close()
+	if u, err = tlsSrv.Endpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "grpc" || u.RawQuery != "isSecure=true" {
+		t.Errorf("unexpected tls endpoint: %s", u)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip == nil || ip.IsUnspecified() {
+		t.Errorf("expected a host ip, got %s", u.Hostname())
+	}
+
+	// the endpoint of a supplied listener is the address it is bound to
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	if u, err = NewServer(Listener(lis)).Endpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != lis.Addr().String() {
+		t.Errorf("expected %s, got %s", lis.Addr(), u.Host)
+	}
+}
+
+func TestServer_StopDeadline(t *testing.T) {
+	srv := NewServer(Address("127.0.0.1:0"))
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Start(context.Background())
+	}()
+
+	conn, err := grpc.Dial(srv.lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// a pending watch stream blocks the graceful stop
+	stream, err := grpc_health_v1.NewHealthClient(conn).Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := srv.Stop(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected forced stop after deadline, took %s", elapsed)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_Error(t *testing.T) {
+	m := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errors.Forbidden("NO_PERMISSION", "no permission").WithMetadata(map[string]string{"user": "kratos"})
+		}
+	}
+	srv := NewServer(Address("127.0.0.1:0"), Middleware(m))
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+
+	conn, err := DialInsecure(context.Background(), WithEndpoint(srv.lis.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	se := errors.FromError(err)
+	if !errors.IsForbidden(err) || se.Reason != "NO_PERMISSION" || se.Metadata["user"] != "kratos" {
+		t.Errorf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestServer_StreamReplyHeader(t *testing.T) {
+	m := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, _ := transport.FromServerContext(ctx)
+			tr.ReplyHeader().Set("x-before", "1")
+			reply, err := next(ctx, req)
+			tr.ReplyHeader().Set("x-after", "1")
+			return reply, err
+		}
+	}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		if method == "/test.Stream/Send" {
+			tr, _ := transport.FromServerContext(stream.Context())
+			tr.ReplyHeader().Set("x-handler", "1")
+			return stream.SendMsg(&grpc_health_v1.HealthCheckResponse{})
+		}
+		return nil
+	}
+	srv := NewServer(Address("127.0.0.1:0"), Middleware(m), Options(grpc.UnknownServiceHandler(handler)))
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+	conn, err := DialInsecure(context.Background(), WithEndpoint(srv.lis.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		method string
+		want   []string
+		deny   []string
+	}{
+		// the headers set until the first message is sent
+		{method: "/test.Stream/Send", want: []string{"x-before", "x-handler"}, deny: []string{"x-after"}},
+		// the headers set until the call ends without a message
+		{method: "/test.Stream/Empty", want: []string{"x-before", "x-after"}},
+	}
+	for _, test := range tests {
+		stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, test.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = stream.CloseSend(); err != nil {
+			t.Fatal(err)
+		}
+		md, err := stream.Header()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range test.want {
+			if v := md.Get(k); len(v) != 1 || v[0] != "1" {
+				t.Errorf("%s: expected header %s, got %v", test.method, k, md)
+			}
+		}
+		for _, k := range test.deny {
+			if len(md.Get(k)) > 0 {
+				t.Errorf("%s: unexpected header %s", test.method, k)
+			}
+		}
+	}
+}
+
+func TestServer_Bufconn(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := NewServer(Listener(lis))
+	if u, err := srv.Endpoint(); err != nil || u != nil {
+		t.Fatalf("expected no endpoint, got %v %v", u, err)
+	}
+	go func() {
+		if err := srv.Start(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+
+	conn, err := DialInsecure(context.Background(),
+		WithEndpoint("bufnet"),
+		WithOptions(grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("expected serving status, got %s", resp.Status)
+	}
+}
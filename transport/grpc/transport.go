@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"github.com/tiennampham23/kratos-cloned/transport"
+)
+
+var _ transport.Transporter = &Transport{}
+
+// Transport is a gRPC transport.
+type Transport struct {
+	endpoint    string
+	operation   string
+	reqHeader   transport.MetadataCarrier
+	replyHeader transport.MetadataCarrier
+}
+
+// Kind returns the transport kind.
+func (tr *Transport) Kind() transport.Kind {
+	return transport.KindGRPC
+}
+
+// Endpoint returns the transport endpoint.
+func (tr *Transport) Endpoint() string {
+	return tr.endpoint
+}
+
+// Operation returns the transport operation, the full method of the call.
+func (tr *Transport) Operation() string {
+	return tr.operation
+}
+
+// RequestHeader returns the request header.
+func (tr *Transport) RequestHeader() transport.Header {
+	return tr.reqHeader
+}
+
+// ReplyHeader returns the reply header.
+func (tr *Transport) ReplyHeader() transport.Header {
+	return tr.replyHeader
+}
@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"net/http"
+	"strings"
+)
+
+var (
+	_ Header = HTTPHeaderCarrier{}
+	_ Header = MetadataCarrier{}
+)
+
+// HTTPHeaderCarrier is an adapter to use a http.Header as a Header,
+// keys are canonicalized like http.CanonicalHeaderKey does.
+type HTTPHeaderCarrier http.Header
+
+// Get returns the first value associated with the key.
+func (hc HTTPHeaderCarrier) Get(key string) string {
+	return http.Header(hc).Get(key)
+}
+
+// Set replaces the values associated with the key.
+func (hc HTTPHeaderCarrier) Set(key string, value string) {
+	http.Header(hc).Set(key, value)
+}
+
+// Add appends the value to the values associated with the key.
+func (hc HTTPHeaderCarrier) Add(key string, value string) {
+	http.Header(hc).Add(key, value)
+}
+
+// Values returns all values associated with the key.
+func (hc HTTPHeaderCarrier) Values(key string) []string {
+	return http.Header(hc).Values(key)
+}
+
+// Keys lists the keys stored in this carrier.
+func (hc HTTPHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(hc))
+	for k := range hc {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Del deletes the values associated with the key.
+func (hc HTTPHeaderCarrier) Del(key string) {
+	http.Header(hc).Del(key)
+}
+
+// MetadataCarrier is an adapter to use a plain metadata map as a Header,
+// keys are lower-cased like the gRPC metadata.MD does.
+type MetadataCarrier map[string][]string
+
+// Get returns the first value associated with the key.
+func (mc MetadataCarrier) Get(key string) string {
+	if v := mc[strings.ToLower(key)]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Set replaces the values associated with the key.
+func (mc MetadataCarrier) Set(key string, value string) {
+	mc[strings.ToLower(key)] = []string{value}
+}
+
+// Add appends the value to the values associated with the key.
+func (mc MetadataCarrier) Add(key string, value string) {
+	key = strings.ToLower(key)
+	mc[key] = append(mc[key], value)
+}
+
+// Values returns all values associated with the key.
+func (mc MetadataCarrier) Values(key string) []string {
+	return mc[strings.ToLower(key)]
+}
+
+// Keys lists the keys stored in this carrier.
+func (mc MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc))
+	for k := range mc {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Del deletes the values associated with the key.
+func (mc MetadataCarrier) Del(key string) {
+	delete(mc, strings.ToLower(key))
+}
@@ -0,0 +1,43 @@
+package transport
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestHeaderCarrier(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header Header
+		key    string
+	}{
+		{"http", HTTPHeaderCarrier(http.Header{}), "X-Request-Id"},
+		{"metadata", MetadataCarrier{}, "x-request-id"},
+	}
+	for _, tc := range testCases {
+		h := tc.header
+		if h.Get("x-request-id") != "" {
+			t.Errorf("%s: expected empty value", tc.name)
+		}
+		h.Set("X-Request-ID", "1")
+		h.Add("x-request-id", "2")
+		if h.Get("X-Request-Id") != "1" {
+			t.Errorf("%s: expected 1, got %s", tc.name, h.Get("X-Request-Id"))
+		}
+		if v := h.Values("X-REQUEST-ID"); !reflect.DeepEqual(v, []string{"1", "2"}) {
+			t.Errorf("%s: expected [1 2], got %v", tc.name, v)
+		}
+		if keys := h.Keys(); !reflect.DeepEqual(keys, []string{tc.key}) {
+			t.Errorf("%s: expected [%s], got %v", tc.name, tc.key, keys)
+		}
+		h.Set("x-request-id", "3")
+		if v := h.Values("x-request-id"); !reflect.DeepEqual(v, []string{"3"}) {
+			t.Errorf("%s: expected [3], got %v", tc.name, v)
+		}
+		h.Del("X-Request-Id")
+		if len(h.Keys()) != 0 {
+			t.Errorf("%s: expected no keys, got %v", tc.name, h.Keys())
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"github.com/tiennampham23/kratos-cloned/selector"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// ClientOption is HTTP client option.
+type ClientOption func(*clientOptions)
+
+// clientOptions is HTTP client options.
+type clientOptions struct {
+	ctx          context.Context
+	tlsConf      *tls.Config
+	timeout      time.Duration
+	endpoint     string
+	userAgent    string
+	encoder      EncodeRequestFunc
+	decoder      DecodeResponseFunc
+	errorDecoder DecodeErrorFunc
+	transport    http.RoundTripper
+	selector     selector.Selector
+	discovery    registry.Discovery
+	ms           []middleware.Middleware
+	block        bool
+}
+
+// WithTransport with client transport.
+func WithTransport(trans http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.transport = trans
+	}
+}
+
+// WithTimeout with client request timeout.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithUserAgent with client user agent.
+func WithUserAgent(ua string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = ua
+	}
+}
+
+// WithMiddleware with client middleware.
+func WithMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.ms = m
+	}
+}
+
+// WithEndpoint with client addr, e.g. 127.0.0.1:8000 or discovery:///service-name.
+func WithEndpoint(endpoint string) ClientOption {
+	return func(o *clientOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithRequestEncoder with client request encoder.
+func WithRequestEncoder(encoder EncodeRequestFunc) ClientOption {
+	return func(o *clientOptions) {
+		o.encoder = encoder
+	}
+}
+
+// WithResponseDecoder with client response decoder.
+func WithResponseDecoder(decoder DecodeResponseFunc) ClientOption {
+	return func(o *clientOptions) {
+		o.decoder = decoder
+	}
+}
+
+// WithErrorDecoder with client error decoder.
+func WithErrorDecoder(errorDecoder DecodeErrorFunc) ClientOption {
+	return func(o *clientOptions) {
+		o.errorDecoder = errorDecoder
+	}
+}
+
+// WithDiscovery with client discovery, it resolves the discovery:/// endpoints.
+func WithDiscovery(d registry.Discovery) ClientOption {
+	return func(o *clientOptions) {
+		o.discovery = d
+	}
+}
+
+// WithSelector with client node selector, the default one picks the instances randomly.
+func WithSelector(s selector.Selector) ClientOption {
+	return func(o *clientOptions) {
+		o.selector = s
+	}
+}
+
+// WithBlock with client block, NewClient waits until the discovery resolves the first instances.
+func WithBlock() ClientOption {
+	return func(o *clientOptions) {
+		o.block = true
+	}
+}
+
+// WithTLSConfig with tls config.
+func WithTLSConfig(c *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConf = c
+	}
+}
+
+// Client is an HTTP client.
+type Client struct {
+	opts     clientOptions
+	target   *Target
+	r        *resolver
+	cc       *http.Client
+	insecure bool
+}
+
+// NewClient returns an HTTP client.
+func NewClient(ctx context.Context, opts ...ClientOption) (*Client, error) {
+	options := clientOptions{
+		ctx:          ctx,
+		timeout:      2000 * time.Millisecond,
+		encoder:      DefaultRequestEncoder,
+		decoder:      DefaultResponseDecoder,
+		errorDecoder: DefaultErrorDecoder,
+		transport:    http.DefaultTransport,
+		selector:     selector.Random(),
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.tlsConf != nil {
+		if tr, ok := options.transport.(*http.Transport); ok {
+			tr = tr.Clone()
+			tr.TLSClientConfig = options.tlsConf
+			options.transport = tr
+		}
+	}
+	insecure := options.tlsConf == nil
+	target, err := parseTarget(options.endpoint, insecure)
+	if err != nil {
+		return nil, err
+	}
+	var r *resolver
+	if target.Scheme == "discovery" {
+		if options.discovery == nil {
+			return nil, fmt.Errorf("[http client] discovery is required for the endpoint: %s", options.endpoint)
+		}
+		if r, err = newResolver(ctx, options.discovery, target, options.block, insecure); err != nil {
+			return nil, fmt.Errorf("[http client] new resolver failed!err: %v", err)
+		}
+	}
+	return &Client{
+		opts:     options,
+		target:   target,
+		insecure: insecure,
+		r:        r,
+		cc: &http.Client{
+			Timeout:   options.timeout,
+			Transport: options.transport,
+		},
+	}, nil
+}
+
+// CallOption configures a Call before it starts or extracts information from a Call after it completes.
+type CallOption func(*callInfo)
+
+type callInfo struct {
+	contentType string
+	operation   string
+}
+
+// ContentType with request content type, it chooses the encoding of the request body.
+func ContentType(contentType string) CallOption {
+	return func(c *callInfo) {
+		c.contentType = contentType
+	}
+}
+
+// Operation is serviceMethod call option.
+func Operation(operation string) CallOption {
+	return func(c *callInfo) {
+		c.operation = operation
+	}
+}
+
+// Invoke makes an rpc call procedure for remote service, the args are encoded by the
+// content type and the response body is decoded into reply, a non-2xx response is
+// returned as the error decoded by the error decoder.
+func (client *Client) Invoke(ctx context.Context, method, path string, args interface{}, reply interface{}, opts ...CallOption) error {
+	c := callInfo{
+		contentType: "application/json",
+		operation:   path,
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+	var (
+		body    io.Reader
+		getBody func() (io.ReadCloser, error)
+	)
+	if args != nil {
+		data, err := client.opts.encoder(ctx, c.contentType, args)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+		getBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(data)), nil
+		}
+	}
+	scheme := "https"
+	if client.insecure {
+		scheme = "http"
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf("%s://%s%s", scheme, client.target.Authority, path), body)
+	if err != nil {
+		return err
+	}
+	if args != nil {
+		req.GetBody = getBody
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	req.Header.Set("Accept", c.contentType)
+	if client.opts.userAgent != "" {
+		req.Header.Set("User-Agent", client.opts.userAgent)
+	}
+	ctx = transport.NewClientContext(ctx, &Transport{
+		endpoint:     client.opts.endpoint,
+		operation:    c.operation,
+		reqHeader:    transport.HTTPHeaderCarrier(req.Header),
+		replyHeader:  transport.HTTPHeaderCarrier{},
+		request:      req,
+		pathTemplate: path,
+	})
+	return client.invoke(ctx, req, args, reply)
+}
+
+func (client *Client) invoke(ctx context.Context, req *http.Request, args interface{}, reply interface{}) error {
+	h := func(ctx context.Context, in interface{}) (interface{}, error) {
+		// every attempt, e.g. of a retry middleware, sends the whole body
+		r := req.WithContext(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		res, err := client.do(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if tr, ok := transport.FromClientContext(ctx); ok {
+			if tr, ok := tr.(*Transport); ok {
+				tr.replyHeader = transport.HTTPHeaderCarrier(res.Header)
+			}
+		}
+		if err := client.opts.decoder(ctx, res, reply); err != nil {
+			return nil, err
+		}
+		return reply, nil
+	}
+	_, err := middleware.Chain(client.opts.ms...)(h)(ctx, args)
+	return err
+}
+
+// Do sends an HTTP request to the selected instance, it returns the error
+// decoded by the error decoder if the response status code is not 2xx.
+func (client *Client) Do(req *http.Request) (*http.Response, error) {
+	return client.do(req.Context(), req)
+}
+
+func (client *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if client.r != nil {
+		instance, err := client.opts.selector.Select(ctx, client.r.fetch())
+		if err != nil {
+			return nil, err
+		}
+		addr := client.r.address(instance)
+		if addr == "" {
+			return nil, selector.ErrNoAvailable
+		}
+		req.URL.Host = addr
+		req.Host = addr
+	}
+	res, err := client.cc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := client.opts.errorDecoder(ctx, res); err != nil {
+		// a custom error decoder may leave the body open
+		_ = res.Body.Close()
+		return nil, err
+	}
+	return res, nil
+}
+
+// Close stops the resolver and closes the idle connections of the transport,
+// the connections in use are closed once their requests complete.
+func (client *Client) Close() error {
+	client.cc.CloseIdleConnections()
+	if client.r != nil {
+		return client.r.Close()
+	}
+	return nil
+}
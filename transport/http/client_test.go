@@ -0,0 +1,327 @@
+package http
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/errors"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mockDiscovery struct {
+	instances []*registry.ServiceInstance
+}
+
+func (d *mockDiscovery) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return d.instances, nil
+}
+
+func (d *mockDiscovery) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return &mockWatcher{ctx: ctx, instances: d.instances}, nil
+}
+
+type mockWatcher struct {
+	ctx       context.Context
+	instances []*registry.ServiceInstance
+	sent      bool
+}
+
+func (w *mockWatcher) Next() ([]*registry.ServiceInstance, error) {
+	if !w.sent {
+		w.sent = true
+		return w.instances, nil
+	}
+	<-w.ctx.Done()
+	return nil, w.ctx.Err()
+}
+
+func (w *mockWatcher) Stop() error {
+	return nil
+}
+
+type testUser struct {
+	Name string `json:"name" xml:"name" yaml:"name"`
+}
+
+func newTestServer(t *testing.T) *Server {
+	srv := NewServer(Address("127.0.0.1:0"))
+	r := srv.Route("/v1")
+	r.GET("/users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		_ = DefaultResponseEncoder(w, r, &testUser{Name: Vars(r)["name"]})
+	})
+	r.GET("/forbidden", func(w http.ResponseWriter, r *http.Request) {
+		DefaultErrorEncoder(w, r, errors.Forbidden("NO_PERMISSION", "no permission").WithMetadata(map[string]string{"user": "kratos"}))
+	})
+	r.POST("/users", func(w http.ResponseWriter, r *http.Request) {
+		var u testUser
+		if err := DefaultRequestDecoder(r, &u); err != nil {
+			DefaultErrorEncoder(w, r, err)
+			return
+		}
+		_ = DefaultResponseEncoder(w, r, &u)
+	})
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	t.Cleanup(func() {
+		_ = srv.Stop(context.Background())
+	})
+	return srv
+}
+
+func TestClient(t *testing.T) {
+	srv := newTestServer(t)
+	var operation string
+	m := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromClientContext(ctx)
+			if !ok {
+				t.Fatal("expected client transport in context")
+			}
+			operation = tr.Operation()
+			return next(ctx, req)
+		}
+	}
+	client, err := NewClient(context.Background(),
+		WithEndpoint(srv.lis.Addr().String()),
+		WithMiddleware(m),
+		WithUserAgent("kratos"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var reply testUser
+	if err = client.Invoke(context.Background(), http.MethodGet, "/v1/users/kratos", nil, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Name != "kratos" {
+		t.Errorf("expected kratos, got %s", reply.Name)
+	}
+	if operation != "/v1/users/kratos" {
+		t.Errorf("unexpected operation: %s", operation)
+	}
+
+	for _, contentType := range []string{"application/json", "application/xml", "application/yaml", "application/x-www-form-urlencoded"} {
+		reply = testUser{}
+		err = client.Invoke(context.Background(), http.MethodPost, "/v1/users", &testUser{Name: "go"}, &reply,
+			Operation("CreateUser"), ContentType(contentType))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reply.Name != "go" || operation != "CreateUser" {
+			t.Errorf("%s: unexpected reply %s for operation %s", contentType, reply.Name, operation)
+		}
+	}
+
+	err = client.Invoke(context.Background(), http.MethodPost, "/v1/users", "not a user", &reply)
+	if !errors.IsBadRequest(err) || errors.Reason(err) != "CODEC" {
+		t.Errorf("expected bad request error, got %v", err)
+	}
+
+	err = client.Invoke(context.Background(), http.MethodGet, "/v1/unknown", nil, &reply)
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	err = client.Invoke(context.Background(), http.MethodGet, "/v1/forbidden", nil, &reply)
+	if se := errors.FromError(err); se.Code != http.StatusForbidden || se.Reason != "NO_PERMISSION" || se.Metadata["user"] != "kratos" {
+		t.Errorf("expected forbidden error, got %v", err)
+	}
+
+	err = client.Invoke(context.Background(), http.MethodPost, "/v1/users", "invalid", &reply, ContentType("application/unknown"))
+	if err == nil || !strings.Contains(err.Error(), "unsupported content type") {
+		t.Errorf("expected unsupported content type error, got %v", err)
+	}
+}
+
+func TestClient_Discovery(t *testing.T) {
+	srv := newTestServer(t)
+	u, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &mockDiscovery{instances: []*registry.ServiceInstance{
+		{ID: "1", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000?isSecure=false", u.String()}},
+		{ID: "2", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9001?isSecure=false"}},
+	}}
+	client, err := NewClient(context.Background(),
+		WithEndpoint("discovery:///helloworld"),
+		WithDiscovery(d),
+		WithBlock(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	for i := 0; i < 3; i++ {
+		var reply testUser
+		if err = client.Invoke(context.Background(), http.MethodGet, "/v1/users/kratos", nil, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if reply.Name != "kratos" {
+			t.Errorf("expected kratos, got %s", reply.Name)
+		}
+	}
+
+	if _, err = NewClient(context.Background(), WithEndpoint("discovery:///helloworld")); err == nil {
+		t.Error("expected error without discovery")
+	}
+}
+
+func TestClient_RetryBody(t *testing.T) {
+	srv := newTestServer(t)
+	var names []string
+	retry := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			for i := 0; i < 2; i++ {
+				reply, err := next(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				names = append(names, reply.(*testUser).Name)
+			}
+			return req, nil
+		}
+	}
+	client, err := NewClient(context.Background(), WithEndpoint(srv.lis.Addr().String()), WithMiddleware(retry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var reply testUser
+	if err = client.Invoke(context.Background(), http.MethodPost, "/v1/users", &testUser{Name: "go"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "go" || names[1] != "go" {
+		t.Errorf("expected the body on every attempt, got %v", names)
+	}
+}
+
+type closeBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+type bodyTransport struct {
+	body *closeBody
+}
+
+func (tr *bodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: tr.body, Request: req}, nil
+}
+
+func TestClient_ErrorDecoderBody(t *testing.T) {
+	tr := &bodyTransport{body: &closeBody{Reader: strings.NewReader("error")}}
+	client, err := NewClient(context.Background(),
+		WithEndpoint("127.0.0.1:0"),
+		WithTransport(tr),
+		WithErrorDecoder(func(ctx context.Context, res *http.Response) error {
+			return errors.InternalServer("UNKNOWN", "")
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var reply testUser
+	if err = client.Invoke(context.Background(), http.MethodGet, "/v1/users/kratos", nil, &reply); !errors.IsInternalServer(err) {
+		t.Errorf("expected internal server error, got %v", err)
+	}
+	if !tr.body.closed {
+		t.Error("expected the response body to be closed")
+	}
+	_, _ = ioutil.ReadAll(tr.body)
+}
+
+type failWatcher struct {
+	ch      chan []*registry.ServiceInstance
+	stopped chan struct{}
+	lock    sync.Mutex
+	fails   int
+}
+
+func (w *failWatcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case ins := <-w.ch:
+		return ins, nil
+	case <-w.stopped:
+		w.lock.Lock()
+		w.fails++
+		w.lock.Unlock()
+		return nil, errors.ServiceUnavailable("STOPPED", "watcher stopped")
+	}
+}
+
+func (w *failWatcher) Stop() error {
+	close(w.stopped)
+	return nil
+}
+
+type failDiscovery struct {
+	w *failWatcher
+}
+
+func (d *failDiscovery) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return nil, nil
+}
+
+func (d *failDiscovery) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return d.w, nil
+}
+
+func TestResolver_Close(t *testing.T) {
+	w := &failWatcher{ch: make(chan []*registry.ServiceInstance, 1), stopped: make(chan struct{})}
+	w.ch <- []*registry.ServiceInstance{{Name: "helloworld", Endpoints: []string{"http://127.0.0.1:8000?isSecure=false"}}}
+	r, err := newResolver(context.Background(), &failDiscovery{w: w}, &Target{Endpoint: "helloworld"}, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// a watch goroutine still running after Close retries Next every second
+	time.Sleep(1500 * time.Millisecond)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.fails != 1 {
+		t.Errorf("expected the watch goroutine to exit after Close, Next failed %d times", w.fails)
+	}
+}
+
+type idleTransport struct {
+	http.RoundTripper
+	closed bool
+}
+
+func (tr *idleTransport) CloseIdleConnections() {
+	tr.closed = true
+}
+
+func TestClient_Close(t *testing.T) {
+	tr := &idleTransport{RoundTripper: http.DefaultTransport}
+	client, err := NewClient(context.Background(), WithEndpoint("127.0.0.1:0"), WithTransport(tr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !tr.closed {
+		t.Error("expected the idle connections to be closed")
+	}
+}
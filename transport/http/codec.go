@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/tiennampham23/kratos-cloned/encoding"
+	"github.com/tiennampham23/kratos-cloned/encoding/json"
+	"github.com/tiennampham23/kratos-cloned/errors"
+	"github.com/tiennampham23/kratos-cloned/internal/httputil"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	// init the built-in codecs for the content negotiation.
+	_ "github.com/tiennampham23/kratos-cloned/encoding/form"
+	_ "github.com/tiennampham23/kratos-cloned/encoding/proto"
+	_ "github.com/tiennampham23/kratos-cloned/encoding/xml"
+	_ "github.com/tiennampham23/kratos-cloned/encoding/yaml"
+)
+
+// EncodeErrorFunc is encode error func.
+type EncodeErrorFunc func(http.ResponseWriter, *http.Request, error)
+
+// EncodeRequestFunc is request encode func.
+type EncodeRequestFunc func(ctx context.Context, contentType string, in interface{}) (body []byte, err error)
+
+// DecodeResponseFunc is response decode func.
+type DecodeResponseFunc func(ctx context.Context, res *http.Response, out interface{}) error
+
+// DecodeErrorFunc is decode error func, it returns the error of a non-2xx response.
+type DecodeErrorFunc func(ctx context.Context, res *http.Response) error
+
+// CodecForRequest returns the encoding.Codec of the request header, e.g. the
+// "Content-Type" of the request body or the "Accept" of the response body,
+// the JSON codec is returned when no registered codec matches.
+func CodecForRequest(r *http.Request, name string) (encoding.Codec, bool) {
+	for _, values := range r.Header[http.CanonicalHeaderKey(name)] {
+		for _, value := range strings.Split(values, ",") {
+			if codec := encoding.GetCodec(httputil.ContentSubtype(value)); codec != nil {
+				return codec, true
+			}
+		}
+	}
+	return encoding.GetCodec(json.Name), false
+}
+
+// CodecForResponse returns the encoding.Codec of the response "Content-Type".
+func CodecForResponse(r *http.Response) encoding.Codec {
+	if codec := encoding.GetCodec(httputil.ContentSubtype(r.Header.Get("Content-Type"))); codec != nil {
+		return codec
+	}
+	return encoding.GetCodec(json.Name)
+}
+
+// DefaultRequestDecoder decodes the request body into v by its "Content-Type".
+func DefaultRequestDecoder(r *http.Request, v interface{}) error {
+	codec, _ := CodecForRequest(r, "Content-Type")
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err = codec.Unmarshal(data, v); err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	return nil
+}
+
+// DefaultResponseEncoder encodes v into the response body by the request "Accept".
+func DefaultResponseEncoder(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	codec, _ := CodecForRequest(r, "Accept")
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", httputil.ContentType(codec.Name()))
+	_, err = w.Write(data)
+	return err
+}
+
+// DefaultErrorEncoder encodes the error to the HTTP response, the body is the
+// errors.Error encoded by the request "Accept" and the status is its code, or
+// 500 when the code is not a valid HTTP status code. The body is encoded in JSON
+// when the codec of the "Accept" cannot encode the error, e.g. form or proto.
+func DefaultErrorEncoder(w http.ResponseWriter, r *http.Request, err error) {
+	se := errors.FromError(err)
+	codec, _ := CodecForRequest(r, "Accept")
+	body, err := codec.Marshal(se)
+	if err != nil {
+		codec = encoding.GetCodec(json.Name)
+		if body, err = codec.Marshal(se); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+	code := int(se.Code)
+	if code < 100 || code > 999 {
+		code = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", httputil.ContentType(codec.Name()))
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+// DefaultRequestEncoder is an HTTP request encoder, it encodes in by the codec of the content type.
+func DefaultRequestEncoder(_ context.Context, contentType string, in interface{}) ([]byte, error) {
+	codec := encoding.GetCodec(httputil.ContentSubtype(contentType))
+	if codec == nil {
+		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+	}
+	return codec.Marshal(in)
+}
+
+// DefaultResponseDecoder is an HTTP response decoder, it decodes the body by the response "Content-Type".
+func DefaultResponseDecoder(_ context.Context, res *http.Response, v interface{}) error {
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if v == nil || len(data) == 0 {
+		return nil
+	}
+	return CodecForResponse(res).Unmarshal(data, v)
+}
+
+// DefaultErrorDecoder is an HTTP error decoder, it decodes the body of a non-2xx
+// response into an errors.Error, whose code is the response status code.
+func DefaultErrorDecoder(_ context.Context, res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return nil
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err == nil {
+		e := new(errors.Error)
+		if err = CodecForResponse(res).Unmarshal(data, e); err == nil {
+			e.Code = int32(res.StatusCode)
+			return e
+		}
+		return errors.New(res.StatusCode, errors.UnknownReason, string(data))
+	}
+	return errors.New(res.StatusCode, errors.UnknownReason, err.Error())
+}
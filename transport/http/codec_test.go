@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCodecForRequest(t *testing.T) {
+	testCases := []struct {
+		accept   string
+		expected string
+		ok       bool
+	}{
+		{"application/xml", "xml", true},
+		{"text/html, application/yaml;q=0.9", "yaml", true},
+		{"application/x-www-form-urlencoded", "x-www-form-urlencoded", true},
+		{"text/html", "json", false},
+		{"", "json", false},
+	}
+	for _, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.accept != "" {
+			r.Header.Set("Accept", tc.accept)
+		}
+		codec, ok := CodecForRequest(r, "Accept")
+		if codec.Name() != tc.expected || ok != tc.ok {
+			t.Errorf("%s: expected %s %v, got %s %v", tc.accept, tc.expected, tc.ok, codec.Name(), ok)
+		}
+	}
+}
+
+func TestDefaultErrorEncoder(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+	DefaultErrorEncoder(w, r, errors.NotFound("USER_NOT_FOUND", "user not found"))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/xml" {
+		t.Errorf("expected xml content type, got %s", ct)
+	}
+	expected := "<Error><code>404</code><reason>USER_NOT_FOUND</reason><message>user not found</message></Error>"
+	if w.Body.String() != expected {
+		t.Errorf("expected %s, got %s", expected, w.Body.String())
+	}
+}
+
+func TestDefaultErrorEncoder_InvalidCode(t *testing.T) {
+	for _, code := range []int{0, 99, 1000} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		DefaultErrorEncoder(w, r, errors.New(code, "INVALID", "invalid code"))
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("code %d: expected 500, got %d", code, w.Code)
+		}
+	}
+}
+
+func TestDefaultErrorEncoder_Accept(t *testing.T) {
+	err := errors.Forbidden("NO_PERMISSION", "no permission").WithMetadata(map[string]string{"user": "kratos"})
+	testCases := []struct {
+		accept      string
+		contentType string
+	}{
+		// the form and proto codecs cannot encode the error, it falls back to JSON
+		{"application/x-www-form-urlencoded", "application/json"},
+		{"application/proto", "application/json"},
+		{"application/xml", "application/xml"},
+		{"application/yaml", "application/yaml"},
+	}
+	for _, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		DefaultErrorEncoder(w, r, err)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", tc.accept, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Errorf("%s: expected %s, got %s", tc.accept, tc.contentType, ct)
+		}
+		se, e := DefaultErrorDecoder(context.Background(), w.Result()).(*errors.Error)
+		if !e || se.Reason != "NO_PERMISSION" || se.Metadata["user"] != "kratos" {
+			t.Errorf("%s: expected the encoded error, got %v", tc.accept, se)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"github.com/tiennampham23/kratos-cloned/internal/endpoint"
+	"github.com/tiennampham23/kratos-cloned/log"
+	"github.com/tiennampham23/kratos-cloned/registry"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Target is resolver target
+type Target struct {
+	Scheme    string
+	Authority string
+	Endpoint  string
+}
+
+func parseTarget(endpoint string, insecure bool) (*Target, error) {
+	if !strings.Contains(endpoint, "://") {
+		if insecure {
+			endpoint = "http://" + endpoint
+		} else {
+			endpoint = "https://" + endpoint
+		}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	target := &Target{Scheme: u.Scheme, Authority: u.Host}
+	if len(u.Path) > 1 {
+		target.Endpoint = u.Path[1:]
+	}
+	return target, nil
+}
+
+type resolver struct {
+	lock      sync.RWMutex
+	instances []*registry.ServiceInstance
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	watcher  registry.Watcher
+	scheme   string
+	insecure bool
+}
+
+// newResolver watches the service of the target, when block is true it waits
+// until the first instances are resolved or ctx is done.
+func newResolver(ctx context.Context, discovery registry.Discovery, target *Target, block, insecure bool) (*resolver, error) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	watcher, err := discovery.Watch(watchCtx, target.Endpoint)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r := &resolver{
+		ctx:      watchCtx,
+		cancel:   cancel,
+		watcher:  watcher,
+		scheme:   "http",
+		insecure: insecure,
+	}
+	if !insecure {
+		r.scheme = "https"
+	}
+	if block {
+		done := make(chan error, 1)
+		go func() {
+			for {
+				instances, err := watcher.Next()
+				if err != nil {
+					done <- err
+					return
+				}
+				if r.update(instances) {
+					done <- nil
+					return
+				}
+			}
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				_ = r.Close()
+				return nil, err
+			}
+		case <-ctx.Done():
+			_ = r.Close()
+			return nil, ctx.Err()
+		}
+	}
+	go func() {
+		for {
+			instances, err := watcher.Next()
+			if err != nil {
+				// the resolver is closed, the watcher fails with any error
+				if r.ctx.Err() != nil || errors.Is(err, context.Canceled) {
+					return
+				}
+				log.Errorf("http client watch service %v got unexpected error:=%v", target, err)
+				select {
+				case <-r.ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+			r.update(instances)
+		}
+	}()
+	return r, nil
+}
+
+// update keeps the instances having an endpoint for the client scheme,
+// it reports whether any instance was found.
+func (r *resolver) update(instances []*registry.ServiceInstance) bool {
+	filtered := make([]*registry.ServiceInstance, 0, len(instances))
+	for _, in := range instances {
+		if r.address(in) != "" {
+			filtered = append(filtered, in)
+		}
+	}
+	if len(filtered) == 0 {
+		log.Errorf("[http resolver] Zero endpoint found, refused to write, instances: %v", instances)
+		return false
+	}
+	r.lock.Lock()
+	r.instances = filtered
+	r.lock.Unlock()
+	return true
+}
+
+func (r *resolver) fetch() []*registry.ServiceInstance {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.instances
+}
+
+// address returns the host of the instance endpoint matching the client scheme.
+func (r *resolver) address(in *registry.ServiceInstance) string {
+	addr, err := endpoint.ParseEndpoint(in.Endpoints, r.scheme, !r.insecure)
+	if err != nil {
+		return ""
+	}
+	return addr
+}
+
+// Close stops the watcher, the watch goroutine exits once the watcher returns.
+func (r *resolver) Close() error {
+	r.cancel()
+	return r.watcher.Stop()
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"path"
+)
+
+// Router is an HTTP router group sharing a path prefix.
+type Router struct {
+	prefix string
+	srv    *Server
+}
+
+func newRouter(prefix string, srv *Server) *Router {
+	return &Router{
+		prefix: prefix,
+		srv:    srv,
+	}
+}
+
+// Group returns a new router group nested under the current prefix.
+func (r *Router) Group(prefix string) *Router {
+	return newRouter(path.Join(r.prefix, prefix), r.srv)
+}
+
+// Handle registers a new route with a matcher for the URL path and method.
+func (r *Router) Handle(method, relativePath string, h http.HandlerFunc) {
+	r.srv.router.Handle(r.join(relativePath), h).Methods(method)
+}
+
+// GET registers a new GET route for a path with matching handler in the router.
+func (r *Router) GET(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodGet, path, h)
+}
+
+// HEAD registers a new HEAD route for a path with matching handler in the router.
+func (r *Router) HEAD(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodHead, path, h)
+}
+
+// POST registers a new POST route for a path with matching handler in the router.
+func (r *Router) POST(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodPost, path, h)
+}
+
+// PUT registers a new PUT route for a path with matching handler in the router.
+func (r *Router) PUT(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodPut, path, h)
+}
+
+// PATCH registers a new PATCH route for a path with matching handler in the router.
+func (r *Router) PATCH(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodPatch, path, h)
+}
+
+// DELETE registers a new DELETE route for a path with matching handler in the router.
+func (r *Router) DELETE(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodDelete, path, h)
+}
+
+// CONNECT registers a new CONNECT route for a path with matching handler in the router.
+func (r *Router) CONNECT(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodConnect, path, h)
+}
+
+// OPTIONS registers a new OPTIONS route for a path with matching handler in the router.
+func (r *Router) OPTIONS(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodOptions, path, h)
+}
+
+// TRACE registers a new TRACE route for a path with matching handler in the router.
+func (r *Router) TRACE(path string, h http.HandlerFunc) {
+	r.Handle(http.MethodTrace, path, h)
+}
+
+// join keeps the trailing slash of relativePath, so the strictSlash
+// setting of the server decides how it is matched.
+func (r *Router) join(relativePath string) string {
+	p := path.Join(r.prefix, relativePath)
+	if len(relativePath) > 1 && relativePath[len(relativePath)-1] == '/' && p[len(p)-1] != '/' {
+		p += "/"
+	}
+	return p
+}
+
+// Vars returns the path parameters of the current request, if any.
+func Vars(req *http.Request) map[string]string {
+	return mux.Vars(req)
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/errors"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/middleware/selector"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	srv := NewServer()
+	srv.HandleFunc("/index", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("index"))
+	})
+	srv.HandlePrefix("/static/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	r := srv.Route("/v1")
+	r.GET("/users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("get " + Vars(r)["name"]))
+	})
+	r.POST("/users", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("post"))
+	})
+	r.Group("/admin").DELETE("/users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("delete " + Vars(r)["name"]))
+	})
+
+	testCases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/index", http.StatusOK, "index"},
+		{http.MethodGet, "/static/css/app.css", http.StatusOK, "/static/css/app.css"},
+		{http.MethodGet, "/v1/users/kratos", http.StatusOK, "get kratos"},
+		{http.MethodPost, "/v1/users", http.StatusOK, "post"},
+		{http.MethodDelete, "/v1/admin/users/kratos", http.StatusOK, "delete kratos"},
+		{http.MethodPut, "/v1/users/kratos", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/v2/users", http.StatusNotFound, ""},
+		{http.MethodGet, "/index/", http.StatusMovedPermanently, ""},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s %s: expected code %d, got %d", tc.method, tc.path, tc.code, w.Code)
+		}
+		if tc.code == http.StatusOK && w.Body.String() != tc.body {
+			t.Errorf("%s %s: expected body %q, got %q", tc.method, tc.path, tc.body, w.Body.String())
+		}
+	}
+}
+
+func TestRouter_Middleware(t *testing.T) {
+	auth := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if r, ok := req.(*http.Request); ok && r.Header.Get("Authorization") == "" {
+				return nil, errors.Unauthorized("UNAUTHORIZED", "missing token")
+			}
+			return next(ctx, req)
+		}
+	}
+	srv := NewServer(Middleware(selector.Server(auth).Prefix("/admin/").Build()))
+	srv.HandleFunc("/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("admin"))
+	})
+	srv.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("users"))
+	})
+
+	testCases := []struct {
+		path  string
+		token string
+		code  int
+		body  string
+	}{
+		{"/users", "", http.StatusOK, "users"},
+		{"/admin/users", "", http.StatusUnauthorized, `{"code":401,"reason":"UNAUTHORIZED","message":"missing token","metadata":null}`},
+		{"/admin/users", "token", http.StatusOK, "admin"},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", tc.token)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != tc.code || w.Body.String() != tc.body {
+			t.Errorf("%s: expected %d %q, got %d %q", tc.path, tc.code, tc.body, w.Code, w.Body.String())
+		}
+	}
+}
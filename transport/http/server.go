@@ -4,8 +4,12 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
-	"fmt"
 	"github.com/gorilla/mux"
+	"github.com/tiennampham23/kratos-cloned/internal/endpoint"
+	"github.com/tiennampham23/kratos-cloned/internal/host"
+	"github.com/tiennampham23/kratos-cloned/log"
+	"github.com/tiennampham23/kratos-cloned/middleware"
+	"github.com/tiennampham23/kratos-cloned/transport"
 	"net"
 	"net/http"
 	"net/url"
@@ -15,46 +19,223 @@ import (
 // Server is an HTTP server wrapper.
 type Server struct {
 	*http.Server
-	lis net.Listener
-	tlsConf *tls.Config
-	endpoint *url.URL
-	err error
-	network string
-	address string
-	timeout time.Duration
+	lis         net.Listener
+	tlsConf     *tls.Config
+	endpoint    *url.URL
+	err         error
+	network     string
+	address     string
+	timeout     time.Duration
 	strictSlash bool
-	router *mux.Router
+	drainDelay  time.Duration
+	router      *mux.Router
+	ms          []middleware.Middleware
+	ene         EncodeErrorFunc
+
+	readTimeout    time.Duration
+	writeTimeout   time.Duration
+	idleTimeout    time.Duration
+	maxHeaderBytes int
 }
 
 // ServerOption is an HTTP server option.
 type ServerOption func(*Server)
 
+// Network with server network.
+func Network(network string) ServerOption {
+	return func(s *Server) {
+		s.network = network
+	}
+}
+
+// Address with server address.
+func Address(addr string) ServerOption {
+	return func(s *Server) {
+		s.address = addr
+	}
+}
+
+// Timeout with server timeout, it is applied as the deadline of every request.
+func Timeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.timeout = timeout
+	}
+}
+
+// TLSConfig with TLS config.
+func TLSConfig(c *tls.Config) ServerOption {
+	return func(s *Server) {
+		s.tlsConf = c
+	}
+}
+
+// Listener with server lis.
+func Listener(lis net.Listener) ServerOption {
+	return func(s *Server) {
+		s.lis = lis
+	}
+}
+
+// StrictSlash is with mux's StrictSlash
+// If true, when the path pattern is "/path/", accessing "/path" will
+// redirect to the former and vice versa.
+func StrictSlash(strictSlash bool) ServerOption {
+	return func(s *Server) {
+		s.strictSlash = strictSlash
+	}
+}
+
+// Middleware with server middleware, it is applied to every route.
+func Middleware(m ...middleware.Middleware) ServerOption {
+	return func(s *Server) {
+		s.ms = m
+	}
+}
+
+// ErrorEncoder with error encoder, it encodes the errors returned by the middleware.
+func ErrorEncoder(en EncodeErrorFunc) ServerOption {
+	return func(s *Server) {
+		s.ene = en
+	}
+}
+
+// ReadTimeout with the maximum duration for reading the entire request, including the body.
+func ReadTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.readTimeout = timeout
+	}
+}
+
+// WriteTimeout with the maximum duration before timing out writes of the response.
+func WriteTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.writeTimeout = timeout
+	}
+}
+
+// IdleTimeout with the maximum amount of time to wait for the next request when keep-alives are enabled.
+func IdleTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.idleTimeout = timeout
+	}
+}
+
+// MaxHeaderBytes with the maximum number of bytes the server will read parsing the request header.
+func MaxHeaderBytes(n int) ServerOption {
+	return func(s *Server) {
+		s.maxHeaderBytes = n
+	}
+}
+
+// DrainDelay with server drain delay, the time to wait before the server
+// stops accepting connections, so load balancers can notice the deregistration.
+func DrainDelay(delay time.Duration) ServerOption {
+	return func(s *Server) {
+		s.drainDelay = delay
+	}
+}
+
 func NewServer(opts ...ServerOption) *Server {
 	srv := &Server{
-		network: "tcp",
-		address: ":0",
-		timeout: 1 * time.Second,
+		network:     "tcp",
+		address:     ":0",
+		timeout:     1 * time.Second,
 		strictSlash: true,
+		ene:         DefaultErrorEncoder,
 	}
 	for _, o := range opts {
 		o(srv)
 	}
 	srv.router = mux.NewRouter().StrictSlash(srv.strictSlash)
+	srv.router.Use(srv.filter)
 	srv.Server = &http.Server{
-		TLSConfig: srv.tlsConf,
+		Handler:        srv,
+		TLSConfig:      srv.tlsConf,
+		ReadTimeout:    srv.readTimeout,
+		WriteTimeout:   srv.writeTimeout,
+		IdleTimeout:    srv.idleTimeout,
+		MaxHeaderBytes: srv.maxHeaderBytes,
 	}
 	srv.err = srv.listenAndEndpoint()
 	return srv
 }
 
+// Route registers an HTTP router group with the given path prefix.
+func (s *Server) Route(prefix string) *Router {
+	return newRouter(prefix, s)
+}
+
+// Handle registers a new route with a matcher for the URL path.
+func (s *Server) Handle(path string, h http.Handler) {
+	s.router.Handle(path, h)
+}
+
+// HandlePrefix registers a new route with a matcher for the URL path prefix.
+func (s *Server) HandlePrefix(prefix string, h http.Handler) {
+	s.router.PathPrefix(prefix).Handler(h)
+}
+
+// HandleFunc registers a new route with a matcher for the URL path.
+func (s *Server) HandleFunc(path string, h http.HandlerFunc) {
+	s.router.HandleFunc(path, h)
+}
+
+// ServeHTTP should write reply headers and data to the ResponseWriter and then return.
+func (s *Server) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if s.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), s.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+	s.router.ServeHTTP(res, req)
+}
+
+// filter injects the Transporter into the request context and runs the
+// server middleware around the matched route handler.
+func (s *Server) filter(next http.Handler) http.Handler {
+	chain := middleware.Chain(s.ms...)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		pathTemplate := req.URL.Path
+		if route := mux.CurrentRoute(req); route != nil {
+			// /path/123 -> /path/{id}
+			pathTemplate, _ = route.GetPathTemplate()
+		}
+		tr := &Transport{
+			operation:    pathTemplate,
+			reqHeader:    transport.HTTPHeaderCarrier(req.Header),
+			replyHeader:  transport.HTTPHeaderCarrier(w.Header()),
+			request:      req,
+			pathTemplate: pathTemplate,
+		}
+		if s.endpoint != nil {
+			tr.endpoint = s.endpoint.String()
+		}
+		ctx := transport.NewServerContext(req.Context(), tr)
+		h := func(ctx context.Context, in interface{}) (interface{}, error) {
+			r, ok := in.(*http.Request)
+			if !ok {
+				r = req
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return nil, nil
+		}
+		if _, err := chain(h)(ctx, req); err != nil {
+			s.ene(w, req, err)
+		}
+	})
+}
+
 func (s *Server) Start(ctx context.Context) error {
 	if s.err != nil {
 		return s.err
 	}
+	// request contexts look up the values stored in ctx, e.g. the AppInfo
+	// injected by App.Run, but are not cancelled with it, so the in-flight
+	// requests complete while the server is stopping.
 	s.BaseContext = func(net.Listener) context.Context {
-		return ctx
+		return &baseContext{Context: context.Background(), base: ctx}
 	}
-	fmt.Println(fmt.Sprintf("[HTTP] server listening on: %s", s.lis.Addr().String()))
+	log.Infof("[HTTP] server listening on: %s", s.lis.Addr().String())
 	var err error
 	if s.tlsConf != nil {
 		err = s.ServeTLS(s.lis, "", "")
@@ -67,18 +248,83 @@ func (s *Server) Start(ctx context.Context) error {
 	return nil
 }
 
+// Stop gracefully stops the HTTP server, waiting for in-flight requests to
+// complete until the ctx deadline, then closes the remaining connections
+// and returns the ctx error.
 func (s *Server) Stop(ctx context.Context) error {
-	fmt.Println("Stopped")
-	return nil
+	if s.drainDelay > 0 {
+		timer := time.NewTimer(s.drainDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+	log.Info("[HTTP] server stopping")
+	err := s.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		log.Warnf("[HTTP] server forced to close: %v", err)
+		if cErr := s.Close(); cErr != nil {
+			return cErr
+		}
+	}
+	return err
+}
+
+// baseContext looks up the values missing in the request context from the
+// context passed to Start, without its cancellation.
+type baseContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c *baseContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.base.Value(key)
+}
+
+// Endpoint return a real address to registry endpoint, it is nil for a non-TCP listener.
+// examples:
+//
+//	http://127.0.0.1:8000?isSecure=false
+//	https://127.0.0.1:8000?isSecure=true
+func (s *Server) Endpoint() (*url.URL, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.endpoint, nil
 }
 
 func (s *Server) listenAndEndpoint() error {
-	if s.lis == nil {
+	// the host of a supplied listener is the one it is bound to, not the address option
+	hostPort, created := s.address, s.lis == nil
+	if created {
 		lis, err := net.Listen(s.network, s.address)
 		if err != nil {
 			return err
 		}
 		s.lis = lis
+	} else {
+		hostPort = s.lis.Addr().String()
+	}
+	// only a TCP listener has a registry endpoint, e.g. a unix socket has none
+	if _, ok := host.Port(s.lis); !ok {
+		return nil
+	}
+	addr, err := host.Extract(hostPort, s.lis)
+	if err != nil {
+		// a supplied listener is left to the caller
+		if created {
+			_ = s.lis.Close()
+		}
+		return err
 	}
+	scheme := "http"
+	if s.tlsConf != nil {
+		scheme = "https"
+	}
+	s.endpoint = endpoint.NewEndpoint(scheme, addr, s.tlsConf != nil)
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,252 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testKey struct{}
+
+func TestServer_BaseContext(t *testing.T) {
+	srv := NewServer(Address("127.0.0.1:0"))
+	srv.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, _ := r.Context().Value(testKey{}).(string)
+		_, _ = w.Write([]byte(v))
+	})
+	ctx := context.WithValue(context.Background(), testKey{}, "kratos")
+	go func() {
+		_ = srv.Start(ctx)
+	}()
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/", srv.lis.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "kratos" {
+		t.Errorf("expected kratos, got %s", body)
+	}
+}
+
+func TestServer_Stop(t *testing.T) {
+	release := make(chan struct{})
+	srv := NewServer(Address("127.0.0.1:0"))
+	srv.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Start(context.Background())
+	}()
+
+	result := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://%s/", srv.lis.Addr().String()))
+		if err != nil {
+			result <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		result <- string(body)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- srv.Stop(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if body := <-result; body != "done" {
+		t.Errorf("expected in-flight request to complete, got %s", body)
+	}
+}
+
+func TestServer_StopDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	srv := NewServer(Address("127.0.0.1:0"))
+	srv.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	go func() {
+		_, _ = http.Get(fmt.Sprintf("http://%s/", srv.lis.Addr().String()))
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := srv.Stop(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected forced close after deadline, took %s", elapsed)
+	}
+}
+
+func TestServer_DrainDelay(t *testing.T) {
+	srv := NewServer(Address("127.0.0.1:0"), DrainDelay(200*time.Millisecond))
+	go func() {
+		_ = srv.Start(context.Background())
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	if err := srv.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected drain delay before stop, took %s", elapsed)
+	}
+}
+
+func TestServer_Options(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	tlsConf := &tls.Config{}
+	srv := NewServer(
+		Network("tcp"),
+		Listener(lis),
+		Timeout(time.Minute),
+		TLSConfig(tlsConf),
+		StrictSlash(false),
+		ReadTimeout(time.Second),
+		WriteTimeout(2*time.Second),
+		IdleTimeout(3*time.Second),
+		MaxHeaderBytes(1024),
+	)
+	if srv.lis != lis {
+		t.Error("expected listener to be used")
+	}
+	if srv.timeout != time.Minute || srv.strictSlash || srv.TLSConfig != tlsConf {
+		t.Errorf("unexpected server options: %v %v %v", srv.timeout, srv.strictSlash, srv.TLSConfig)
+	}
+	if srv.ReadTimeout != time.Second || srv.WriteTimeout != 2*time.Second ||
+		srv.IdleTimeout != 3*time.Second || srv.MaxHeaderBytes != 1024 {
+		t.Errorf("unexpected http server options: %v %v %v %v",
+			srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout, srv.MaxHeaderBytes)
+	}
+}
+
+func TestServer_Timeout(t *testing.T) {
+	srv := NewServer(Timeout(100 * time.Millisecond))
+	srv.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("expected request deadline")
+		}
+		select {
+		case <-r.Context().Done():
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case <-time.After(time.Second):
+		}
+	})
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected request to time out, got %d", w.Code)
+	}
+}
+
+func TestServer_Endpoint(t *testing.T) {
+	srv := NewServer(Address("127.0.0.1:0"))
+	defer srv.lis.Close()
+	u, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("http://%s?isSecure=false", srv.lis.Addr().String())
+	if u.String() != expected {
+		t.Errorf("expected %s, got %s", expected, u)
+	}
+
+	tlsSrv := NewServer(Address(":0"), TLSConfig(&tls.Config{}))
+	defer tlsSrv.lis.Close()
+	if u, err = tlsSrv.Endpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "https" || u.RawQuery != "isSecure=true" {
+		t.Errorf("unexpected tls endpoint: %s", u)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip == nil || ip.IsUnspecified() {
+		t.Errorf("expected a host ip, got %s", u.Hostname())
+	}
+
+	// the endpoint of a supplied listener is the address it is bound to
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	if u, err = NewServer(Listener(lis)).Endpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != lis.Addr().String() {
+		t.Errorf("expected %s, got %s", lis.Addr(), u.Host)
+	}
+}
+
+func TestServer_Unix(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "kratos.sock")
+	srv := NewServer(Network("unix"), Address(sock))
+	srv.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("unix"))
+	})
+	if u, err := srv.Endpoint(); err != nil || u != nil {
+		t.Fatalf("expected no endpoint, got %v %v", u, err)
+	}
+	go func() {
+		if err := srv.Start(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}()
+	defer func() {
+		_ = srv.Stop(context.Background())
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err := client.Get("http://unix/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := ioutil.ReadAll(resp.Body); string(body) != "unix" {
+		t.Errorf("expected unix, got %s", body)
+	}
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"net/http"
+)
+
+var _ Transporter = &Transport{}
+
+// Transporter is http Transporter
+type Transporter interface {
+	transport.Transporter
+	Request() *http.Request
+	PathTemplate() string
+}
+
+// Transport is an HTTP transport.
+type Transport struct {
+	endpoint     string
+	operation    string
+	reqHeader    transport.HTTPHeaderCarrier
+	replyHeader  transport.HTTPHeaderCarrier
+	request      *http.Request
+	pathTemplate string
+}
+
+// Kind returns the transport kind.
+func (tr *Transport) Kind() transport.Kind {
+	return transport.KindHTTP
+}
+
+// Endpoint returns the transport endpoint.
+func (tr *Transport) Endpoint() string {
+	return tr.endpoint
+}
+
+// Operation returns the transport operation, the matched route path template.
+func (tr *Transport) Operation() string {
+	return tr.operation
+}
+
+// Request returns the HTTP request.
+func (tr *Transport) Request() *http.Request {
+	return tr.request
+}
+
+// RequestHeader returns the request header.
+func (tr *Transport) RequestHeader() transport.Header {
+	return tr.reqHeader
+}
+
+// ReplyHeader returns the reply header.
+func (tr *Transport) ReplyHeader() transport.Header {
+	return tr.replyHeader
+}
+
+// PathTemplate returns the http path template.
+func (tr *Transport) PathTemplate() string {
+	return tr.pathTemplate
+}
+
+// SetOperation sets Transport operation.
+func SetOperation(ctx context.Context, op string) {
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		if tr, ok := tr.(*Transport); ok {
+			tr.operation = op
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"github.com/tiennampham23/kratos-cloned/transport"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	srv := NewServer()
+	srv.Route("/v1").GET("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tr, ok := transport.FromServerContext(r.Context())
+		if !ok {
+			t.Fatal("expected transport in request context")
+		}
+		if tr.Kind() != transport.KindHTTP {
+			t.Errorf("expected http kind, got %s", tr.Kind())
+		}
+		if tr.Operation() != "/v1/users/{id}" {
+			t.Errorf("expected route template operation, got %s", tr.Operation())
+		}
+		if tr.Endpoint() == "" {
+			t.Error("expected server endpoint")
+		}
+		if tr.RequestHeader().Get("X-Request-Id") != "1" {
+			t.Errorf("unexpected request header: %v", tr.RequestHeader().Keys())
+		}
+		tr.ReplyHeader().Set("X-Request-Id", "1")
+		if htr, ok := tr.(Transporter); !ok || htr.Request().URL.Path != "/v1/users/42" {
+			t.Error("expected http transporter with request")
+		}
+	})
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/42", nil)
+	req.Header.Set("X-Request-Id", "1")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Header().Get("X-Request-Id") != "1" {
+		t.Error("expected reply header to be written")
+	}
+}
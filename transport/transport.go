@@ -11,22 +11,40 @@ func (k Kind) String() string {
 	return string(k)
 }
 
+// Defines a set of transport kind
+const (
+	KindGRPC Kind = "grpc"
+	KindHTTP Kind = "http"
+)
+
 // Server is transport layer.
 type Server interface {
 	Start(ctx context.Context) error
+	// Stop gracefully stops the server until ctx is done, then stops it
+	// forcibly and returns the ctx error.
 	Stop(ctx context.Context) error
 }
 
-// Enpointer is registry endpoint.
+// Enpointer is registry endpoint, the endpoint is nil when the server has none,
+// e.g. when it listens on a unix socket.
 type Enpointer interface {
 	Endpoint() (*url.URL, error)
 }
 
 // Header is the storage medium used by a Header.
 type Header interface {
-	Get(key string) error
-	Set(key string, value string) error
+	// Get returns the first value associated with the key.
+	Get(key string) string
+	// Set replaces the values associated with the key.
+	Set(key string, value string)
+	// Add appends the value to the values associated with the key.
+	Add(key string, value string)
+	// Values returns all values associated with the key.
+	Values(key string) []string
+	// Keys lists the keys stored in this header.
 	Keys() []string
+	// Del deletes the values associated with the key.
+	Del(key string)
 }
 
 // Transporter is transport context value interface.
@@ -51,4 +69,31 @@ type Transporter interface {
 	// http: http.Header
 	// grpc: metadata.MD
 	ReplyHeader() Header
-}
\ No newline at end of file
+}
+
+type (
+	serverTransportKey struct{}
+	clientTransportKey struct{}
+)
+
+// NewServerContext returns a new Context that carries value.
+func NewServerContext(ctx context.Context, tr Transporter) context.Context {
+	return context.WithValue(ctx, serverTransportKey{}, tr)
+}
+
+// FromServerContext returns the Transport value stored in ctx, if any.
+func FromServerContext(ctx context.Context) (tr Transporter, ok bool) {
+	tr, ok = ctx.Value(serverTransportKey{}).(Transporter)
+	return
+}
+
+// NewClientContext returns a new Context that carries value.
+func NewClientContext(ctx context.Context, tr Transporter) context.Context {
+	return context.WithValue(ctx, clientTransportKey{}, tr)
+}
+
+// FromClientContext returns the Transport value stored in ctx, if any.
+func FromClientContext(ctx context.Context) (tr Transporter, ok bool) {
+	tr, ok = ctx.Value(clientTransportKey{}).(Transporter)
+	return
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"context"
+	"testing"
+)
+
+type mockTransport struct {
+	endpoint  string
+	operation string
+}
+
+func (tr *mockTransport) Kind() Kind            { return KindGRPC }
+func (tr *mockTransport) Endpoint() string      { return tr.endpoint }
+func (tr *mockTransport) Operation() string     { return tr.operation }
+func (tr *mockTransport) RequestHeader() Header { return nil }
+func (tr *mockTransport) ReplyHeader() Header   { return nil }
+
+func TestServerContext(t *testing.T) {
+	if _, ok := FromServerContext(context.Background()); ok {
+		t.Fatal("expected no transport in empty context")
+	}
+	ctx := NewServerContext(context.Background(), &mockTransport{operation: "/helloworld.Greeter/SayHello"})
+	tr, ok := FromServerContext(ctx)
+	if !ok {
+		t.Fatal("expected server transport in context")
+	}
+	if tr.Operation() != "/helloworld.Greeter/SayHello" {
+		t.Errorf("unexpected operation: %s", tr.Operation())
+	}
+	if _, ok := FromClientContext(ctx); ok {
+		t.Error("server transport should not be a client transport")
+	}
+}
+
+func TestClientContext(t *testing.T) {
+	ctx := NewClientContext(context.Background(), &mockTransport{endpoint: "discovery:///provider-demo"})
+	tr, ok := FromClientContext(ctx)
+	if !ok {
+		t.Fatal("expected client transport in context")
+	}
+	if tr.Endpoint() != "discovery:///provider-demo" {
+		t.Errorf("unexpected endpoint: %s", tr.Endpoint())
+	}
+	if _, ok := FromServerContext(ctx); ok {
+		t.Error("client transport should not be a server transport")
+	}
+}